@@ -0,0 +1,146 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/notes"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// DefaultPath is the changelog written when the releaser.yaml does not set one
+const DefaultPath = "CHANGELOG.md"
+
+// Header starts a new changelog
+const Header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+`
+
+// Path returns the changelog of the flavor
+func Path(config types.ChangelogConfig, flavor types.Flavor) (string, error) {
+	if config.Path == "" {
+		return DefaultPath, nil
+	}
+
+	tmpl, err := template.New("changelog").Parse(config.Path)
+	if err != nil {
+		return "", fmt.Errorf("invalid changelog path: %w", err)
+	}
+
+	var path strings.Builder
+	if err := tmpl.Execute(&path, struct{ Flavor string }{Flavor: flavor.Name}); err != nil {
+		return "", fmt.Errorf("invalid changelog path: %w", err)
+	}
+	return filepath.Clean(path.String()), nil
+}
+
+// Entry returns the changelog section for the flavor's version, grouping the commits since the previous release of
+// the flavor the same way as the release notes
+func Entry(flavor types.Flavor, flavors []types.Flavor, date time.Time) (string, error) {
+	since, err := notes.PreviousRelease(flavor, flavors)
+	if err != nil {
+		return "", err
+	}
+
+	commits, err := notes.CommitsSince(since)
+	if err != nil {
+		return "", err
+	}
+
+	var entry strings.Builder
+	fmt.Fprintf(&entry, "%s - %s\n\n", heading(flavor), date.Format("2006-01-02"))
+
+	sections := notes.Group(commits)
+	if len(sections) == 0 {
+		entry.WriteString("No changes\n")
+		return entry.String(), nil
+	}
+	if err := notes.WriteMarkdown(&entry, sections, 3); err != nil {
+		return "", err
+	}
+	return entry.String(), nil
+}
+
+// Write adds the entry for the flavor's version to its changelog, creating the changelog if needed. The changelog is
+// left alone when it already has an entry for the version, so it is safe to run again. Returns the path of the
+// changelog and whether it was changed.
+func Write(flavor types.Flavor, flavors []types.Flavor, config types.ChangelogConfig) (string, bool, error) {
+	path, err := Path(config, flavor)
+	if err != nil {
+		return "", false, err
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return path, false, err
+	}
+
+	if HasEntry(string(existing), flavor) {
+		message.Infof("%s already has an entry for %s-%s\n", path, flavor.Version, flavor.Name)
+		return path, false, nil
+	}
+
+	entry, err := Entry(flavor, flavors, time.Now())
+	if err != nil {
+		return path, false, err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return path, false, err
+		}
+	}
+	if err := os.WriteFile(path, []byte(Prepend(string(existing), entry)), 0o644); err != nil {
+		return path, false, err
+	}
+
+	message.Infof("Added %s-%s to %s\n", flavor.Version, flavor.Name, path)
+	return path, true, nil
+}
+
+// HasEntry reports whether the changelog already has a section for the flavor's version
+func HasEntry(changelog string, flavor types.Flavor) bool {
+	for _, line := range strings.Split(changelog, "\n") {
+		if strings.HasPrefix(line, heading(flavor)+" ") || strings.TrimSpace(line) == heading(flavor) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepend inserts the entry above the newest release in the changelog, after its header and any Unreleased section.
+// An empty changelog is started with Header.
+func Prepend(changelog string, entry string) string {
+	if strings.TrimSpace(changelog) == "" {
+		return Header + "\n" + entry
+	}
+
+	lines := strings.SplitAfter(changelog, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") && !strings.HasPrefix(line, "## [Unreleased]") {
+			return strings.Join(lines[:i], "") + entry + "\n" + strings.Join(lines[i:], "")
+		}
+	}
+
+	if !strings.HasSuffix(changelog, "\n") {
+		changelog += "\n"
+	}
+	return changelog + "\n" + entry
+}
+
+func heading(flavor types.Flavor) string {
+	return fmt.Sprintf("## [%s-%s]", flavor.Version, flavor.Name)
+}
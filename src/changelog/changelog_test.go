@@ -0,0 +1,101 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package changelog
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	flavor := types.Flavor{Name: "registry1", Version: "1.0.0-uds.0"}
+
+	path, err := Path(types.ChangelogConfig{}, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "CHANGELOG.md", path)
+
+	path, err = Path(types.ChangelogConfig{Path: "docs/CHANGELOG-{{ .Flavor }}.md"}, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "docs/CHANGELOG-registry1.md", path)
+
+	_, err = Path(types.ChangelogConfig{Path: "{{ .Missing }}"}, flavor)
+	require.ErrorContains(t, err, "invalid changelog path")
+}
+
+func TestPrepend(t *testing.T) {
+	entry := "## [1.1.0-uds.0-upstream] - 2024-10-01\n\n### Features\n\n- add values (aaaaaaaa)\n"
+
+	changelog := Prepend("", entry)
+	require.Equal(t, Header+"\n"+entry, changelog)
+
+	existing := Header + "\n## [Unreleased]\n\n- pending\n\n## [1.0.0-uds.0-upstream] - 2024-09-01\n\nNo changes\n"
+	require.Equal(t, Header+"\n## [Unreleased]\n\n- pending\n\n"+entry+"\n## [1.0.0-uds.0-upstream] - 2024-09-01\n\nNo changes\n", Prepend(existing, entry))
+
+	require.Equal(t, "# Changelog\n\n"+entry, Prepend("# Changelog", entry))
+}
+
+func TestHasEntry(t *testing.T) {
+	changelog := Header + "\n## [1.0.0-uds.0-upstream] - 2024-09-01\n\nNo changes\n"
+
+	require.True(t, HasEntry(changelog, types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}))
+	require.False(t, HasEntry(changelog, types.Flavor{Name: "upstream", Version: "1.0.0-uds.1"}))
+	require.False(t, HasEntry(changelog, types.Flavor{Name: "registry1", Version: "1.0.0-uds.0"}))
+}
+
+func TestWrite(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	for _, subject := range []string{"feat: initial package", "fix(values): correct the port"} {
+		require.NoError(t, os.WriteFile("README.md", []byte(subject), 0o644))
+		_, err = worktree.Add("README.md")
+		require.NoError(t, err)
+		hash, err := worktree.Commit(subject, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		if subject == "feat: initial package" {
+			_, err = repo.CreateTag("1.0.0-uds.0-upstream", hash, nil)
+			require.NoError(t, err)
+		}
+	}
+
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.0-uds.1"}
+	flavors := []types.Flavor{{Name: "upstream", Version: "1.0.0-uds.0"}}
+
+	path, written, err := Write(flavor, flavors, types.ChangelogConfig{})
+	require.NoError(t, err)
+	require.True(t, written)
+	require.Equal(t, "CHANGELOG.md", path)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	changelog := string(contents)
+	require.True(t, strings.HasPrefix(changelog, Header+"\n## [1.0.0-uds.1-upstream] - "))
+	require.Contains(t, changelog, "### Bug Fixes\n\n- **values:** correct the port (")
+	require.NotContains(t, changelog, "initial package")
+
+	// Running again for the same version leaves the changelog alone
+	_, written, err = Write(flavor, flavors, types.ChangelogConfig{})
+	require.NoError(t, err)
+	require.False(t, written)
+	contents, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, changelog, string(contents))
+
+	// Per-flavor changelogs are created in their directory
+	path, written, err = Write(flavor, flavors, types.ChangelogConfig{Path: "changelogs/{{ .Flavor }}.md"})
+	require.NoError(t, err)
+	require.True(t, written)
+	require.Equal(t, "changelogs/upstream.md", path)
+	require.FileExists(t, path)
+}
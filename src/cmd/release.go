@@ -6,13 +6,26 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/defenseunicorns/uds-pk/src/changelog"
+	"github.com/defenseunicorns/uds-pk/src/compat"
+	"github.com/defenseunicorns/uds-pk/src/diff"
+	"github.com/defenseunicorns/uds-pk/src/notes"
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/packager"
 	"github.com/defenseunicorns/uds-pk/src/platforms"
 	"github.com/defenseunicorns/uds-pk/src/platforms/github"
 	"github.com/defenseunicorns/uds-pk/src/platforms/gitlab"
+	"github.com/defenseunicorns/uds-pk/src/status"
+	"github.com/defenseunicorns/uds-pk/src/types"
 	"github.com/defenseunicorns/uds-pk/src/utils"
 	"github.com/defenseunicorns/uds-pk/src/version"
 	"github.com/spf13/cobra"
+	zarfConfig "github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
@@ -21,6 +34,32 @@ var checkBoolOutput bool
 var showVersionOnly bool
 var gitlabTokenVarName string
 var githubTokenVarName string
+var httpConfig types.HTTPConfig
+var publishOpts oci.PublishOptions
+var buildOpts packager.BuildOptions
+var verifyPublished bool
+var plainHTTP bool
+var outputFormat string
+var platformName string
+var platformTokenVarName string
+var listSince string
+var diffOutputFormat string
+var compatFailOnBreaking bool
+var compatRequireBump string
+var skipChecks []string
+var updateYamlCheck bool
+var updateYamlCommit bool
+var updateYamlChangelog bool
+var commitOpts version.CommitOptions
+var commitSign bool
+var signingKeyVarName string
+var prOpts platforms.PullRequestOptions
+var promoteOpts platforms.PromoteOptions
+var promoteCommit bool
+var ociPromoteOpts oci.PromoteOptions
+var snapshot bool
+var snapshotAssets []string
+var pruneOpts platforms.PruneOptions
 
 // checkCmd represents the check command
 var checkCmd = &cobra.Command{
@@ -53,13 +92,34 @@ var checkCmd = &cobra.Command{
 				message.Warnf("Version %s is already tagged\n", versionAndFlavor)
 				return errors.New("no release necessary")
 			}
-		} else {
-			if checkBoolOutput {
-				fmt.Println("true")
-			} else {
-				message.Warnf("Version %s is not tagged\n", versionAndFlavor)
+			return nil
+		}
+
+		if verifyPublished {
+			httpClient, err := utils.NewHTTPClient(utils.MergeHTTPConfig(releaseConfig.HTTP, httpConfig))
+			if err != nil {
+				return err
+			}
+
+			_, err = oci.VerifyPublished(cmd.Context(), currentFlavor, oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: plainHTTP})
+			if errors.Is(err, oci.ErrNotPublished) {
+				if checkBoolOutput {
+					fmt.Println("false")
+					return nil
+				}
+				message.Warnf("Version %s is not published: %s\n", versionAndFlavor, err)
+				return errors.New("release not possible until the package is published")
+			}
+			if err != nil {
+				return err
 			}
 		}
+
+		if checkBoolOutput {
+			fmt.Println("true")
+		} else {
+			message.Warnf("Version %s is not tagged\n", versionAndFlavor)
+		}
 		return nil
 	},
 }
@@ -79,13 +139,17 @@ var showCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		currentFlavor, err = snapshotFlavor(currentFlavor)
+		if err != nil {
+			return err
+		}
 
 		rootCmd.SilenceUsage = true
 
 		if showVersionOnly {
 			fmt.Printf("%s\n", currentFlavor.Version)
 		} else {
-			fmt.Printf("%s-%s\n", currentFlavor.Version, currentFlavor.Name)
+			fmt.Printf("%s\n", oci.FlavorTag(currentFlavor))
 		}
 
 		return nil
@@ -98,7 +162,7 @@ var gitlabCmd = &cobra.Command{
 	Short: "Create a tag and release on GitLab based on flavor",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return platforms.LoadAndTag(releaseDir, args[0], gitlabTokenVarName, gitlab.Platform{})
+		return platforms.LoadAndTag(args[0], releaseOptions(gitlabTokenVarName), gitlab.Platform{})
 	},
 }
 
@@ -108,10 +172,168 @@ var githubCmd = &cobra.Command{
 	Short: "Create a tag and release on GitHub based on flavor",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return platforms.LoadAndTag(releaseDir, args[0], githubTokenVarName, github.Platform{})
+		return platforms.LoadAndTag(args[0], releaseOptions(githubTokenVarName), github.Platform{})
+	},
+}
+
+// prCmd represents the pr command
+var prCmd = &cobra.Command{
+	Use:   "pr flavor",
+	Short: "Open or update a pull/merge request that bumps the flavor to its next version",
+	Long: "Commit the next version of the flavor to the release/<flavor> branch, updating the releaser.yaml, zarf.yaml and uds-bundle.yaml, " +
+		"push it and open or update a pull request (GitHub) or merge request (GitLab) into the current branch with the release notes as the description",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootCmd.SilenceUsage = true
+
+		platform, tokenVarName, err := platformFor(platformName)
+		if err != nil {
+			return err
+		}
+		if platform == nil {
+			return errors.New("unable to detect the platform from the origin remote, set --platform to github or gitlab")
+		}
+		if platformTokenVarName != "" {
+			tokenVarName = platformTokenVarName
+		}
+
+		prOpts.ReleaseDir = releaseDir
+		prOpts.TokenVarName = tokenVarName
+		prOpts.HTTPConfig = httpConfig
+		if commitSign {
+			prOpts.SigningKeyVarName = signingKeyVarName
+		}
+
+		url, err := platforms.OpenReleasePR(args[0], prOpts, platform)
+		if err != nil {
+			return err
+		}
+		fmt.Println(url)
+		return nil
+	},
+}
+
+// promoteCmd represents the promote command
+var promoteCmd = &cobra.Command{
+	Use:   "promote flavor rc-version",
+	Short: "Release a release candidate of the flavor as its final version",
+	Long: "Create the final <version>-<flavor> tag on the commit of the <version>-rc.<N>-<flavor> release candidate, tag its published package " +
+		"and bundles with the final version, copy its release with the notes and assets, and update the releaser.yaml, zarf.yaml and uds-bundle.yaml to the final version",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootCmd.SilenceUsage = true
+
+		platform, tokenVarName, err := platformFor(platformName)
+		if err != nil {
+			return err
+		}
+		if platform == nil {
+			return errors.New("unable to detect the platform from the origin remote, set --platform to github or gitlab")
+		}
+		if platformTokenVarName != "" {
+			tokenVarName = platformTokenVarName
+		}
+
+		promoteOpts.ReleaseDir = releaseDir
+		promoteOpts.TokenVarName = tokenVarName
+		promoteOpts.HTTPConfig = httpConfig
+		promoteOpts.PlainHTTP = plainHTTP
+
+		flavor, url, err := platforms.Promote(args[0], args[1], promoteOpts, platform)
+		if err != nil {
+			return err
+		}
+		fmt.Println(url)
+
+		if !promoteCommit && !commitOpts.Push {
+			return nil
+		}
+		commitOpts.Paths = append(commitOpts.Paths, filepath.ToSlash(filepath.Join(releaseDir, "releaser.yaml")))
+		if commitSign {
+			commitOpts.SigningKeyVarName = signingKeyVarName
+		}
+		if commitOpts.Push {
+			releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+			if err != nil {
+				return err
+			}
+			commitOpts.HTTPConfig = utils.MergeHTTPConfig(releaseConfig.HTTP, httpConfig)
+			commitOpts.TokenVarName = tokenVarName
+		}
+		return version.CommitYamls(flavor, commitOpts)
+	},
+}
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune [flavor]",
+	Short: "Delete the old release candidates and snapshots of a flavor, or of every flavor",
+	Long: "Delete the tags, releases and published packages and bundles of the release candidates and snapshots that the prune policy " +
+		"in the releaser.yaml does not keep. Final versions and the current version of each flavor are never deleted",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootCmd.SilenceUsage = true
+
+		platform, tokenVarName, err := platformFor(platformName)
+		if err != nil {
+			return err
+		}
+		if platformTokenVarName != "" {
+			tokenVarName = platformTokenVarName
+		}
+
+		pruneOpts.ReleaseDir = releaseDir
+		pruneOpts.TokenVarName = tokenVarName
+		pruneOpts.HTTPConfig = httpConfig
+		pruneOpts.PlainHTTP = plainHTTP
+
+		flavorName := ""
+		if len(args) == 1 {
+			flavorName = args[0]
+		}
+
+		pruned, err := platforms.Prune(flavorName, pruneOpts, platform)
+		action := "Deleted"
+		if pruneOpts.DryRun {
+			action = "Would delete"
+		}
+		for _, release := range pruned {
+			if release.Tag != "" {
+				fmt.Printf("%s tag and release %s\n", action, release.Tag)
+			}
+			for _, reference := range release.References {
+				fmt.Printf("%s %s\n", action, reference)
+			}
+		}
+		if err == nil && len(pruned) == 0 {
+			fmt.Println("Nothing to prune")
+		}
+		return err
 	},
 }
 
+// releaseOptions collects the flags shared by the platform commands
+func releaseOptions(tokenVarName string) platforms.ReleaseOptions {
+	return platforms.ReleaseOptions{
+		ReleaseDir:      releaseDir,
+		TokenVarName:    tokenVarName,
+		HTTPConfig:      httpConfig,
+		VerifyPublished: verifyPublished,
+		PlainHTTP:       plainHTTP,
+		SkipChecks:      skipChecks,
+		Snapshot:        snapshot,
+		Assets:          snapshotAssets,
+	}
+}
+
+// snapshotFlavor returns the flavor with the snapshot version of HEAD when --snapshot is set
+func snapshotFlavor(flavor types.Flavor) (types.Flavor, error) {
+	if !snapshot {
+		return flavor, nil
+	}
+	return utils.GetSnapshotFlavor(flavor)
+}
+
 // updateYamlCmd represents the updateyaml command
 var updateYamlCmd = &cobra.Command{
 	Use:     "update-yaml flavor",
@@ -128,13 +350,502 @@ var updateYamlCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		currentFlavor, err = snapshotFlavor(currentFlavor)
+		if err != nil {
+			return err
+		}
+
+		if snapshot && (updateYamlCommit || updateYamlChangelog || commitOpts.Push) {
+			return errors.New("snapshot versions are not committed, --snapshot cannot be used with --commit, --changelog or --push")
+		}
 
 		rootCmd.SilenceUsage = true
 
-		return version.UpdateYamls(currentFlavor)
+		if updateYamlCheck {
+			return verifyYamls(currentFlavor)
+		}
+
+		if err := version.UpdateYamls(currentFlavor, releaseConfig.Hooks); err != nil {
+			return err
+		}
+
+		if updateYamlChangelog {
+			path, _, err := changelog.Write(currentFlavor, releaseConfig.Flavors, releaseConfig.Changelog)
+			if err != nil {
+				return err
+			}
+			commitOpts.Paths = append(commitOpts.Paths, path)
+		}
+
+		if !updateYamlCommit && !commitOpts.Push {
+			return nil
+		}
+
+		if commitSign {
+			commitOpts.SigningKeyVarName = signingKeyVarName
+		}
+		if commitOpts.Push {
+			commitOpts.HTTPConfig = utils.MergeHTTPConfig(releaseConfig.HTTP, httpConfig)
+			commitOpts.TokenVarName = platformTokenVarName
+			if commitOpts.TokenVarName == "" {
+				_, commitOpts.TokenVarName, err = platformFor("")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return version.CommitYamls(currentFlavor, commitOpts)
 	},
 }
 
+// changelogCmd represents the changelog command
+var changelogCmd = &cobra.Command{
+	Use:   "changelog flavor",
+	Short: "Add the changes since the previous release of the flavor to its changelog",
+	Long: "Prepend a Keep a Changelog section for the flavor's version to CHANGELOG.md (or the changelog path in the releaser.yaml), " +
+		"grouping the commits since the previous release of the flavor like the release notes. Does nothing when the version already has a section",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SilenceUsage = true
+
+		_, _, err = changelog.Write(currentFlavor, releaseConfig.Flavors, releaseConfig.Changelog)
+		return err
+	},
+}
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [flavor]",
+	Short: "Validate the releaser.yaml, warning about major version bumps without upgrade notes",
+	Long: "Validate the notes and changelog settings in the releaser.yaml for every flavor (or the given flavor) and warn when a flavor's " +
+		"version bumps the major version of its previous release but the notes directory has no hand-written notes for it",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		flavors := releaseConfig.Flavors
+		if len(args) == 1 {
+			flavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+			if err != nil {
+				return err
+			}
+			flavors = []types.Flavor{flavor}
+		}
+
+		rootCmd.SilenceUsage = true
+
+		if err := notes.Validate(releaseConfig.Notes); err != nil {
+			return err
+		}
+		for _, flavor := range flavors {
+			if _, err := changelog.Path(releaseConfig.Changelog, flavor); err != nil {
+				return err
+			}
+
+			warning, err := notes.MissingUpgradeNotes(releaseConfig.Notes, flavor, releaseConfig.Flavors)
+			if err != nil {
+				return err
+			}
+			if warning != "" {
+				message.Warnf("%s\n", warning)
+			}
+		}
+
+		fmt.Println("releaser.yaml is valid")
+		return nil
+	},
+}
+
+// verifyYamlCmd represents the verify-yaml command
+var verifyYamlCmd = &cobra.Command{
+	Use:   "verify-yaml flavor",
+	Short: "Verify the version fields in the zarf.yaml and uds-bundle.yaml match the flavor without changing them",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SilenceUsage = true
+
+		return verifyYamls(currentFlavor)
+	},
+}
+
+// verifyYamls prints the version fields update-yaml would change as a diff and fails if there are any
+func verifyYamls(flavor types.Flavor) error {
+	mismatches, err := version.VerifyYamls(flavor)
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) == 0 {
+		fmt.Printf("Versions match %s-%s\n", flavor.Version, flavor.Name)
+		return nil
+	}
+
+	if err := version.WriteDiff(os.Stdout, mismatches); err != nil {
+		return err
+	}
+	return fmt.Errorf("version fields do not match %s-%s, run uds-pk release update-yaml %s", flavor.Version, flavor.Name, flavor.Name)
+}
+
+// buildCmd represents the build command
+var buildCmd = &cobra.Command{
+	Use:   "build flavor",
+	Short: "Build the Zarf package, and optionally the bundle, for a flavor",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+		currentFlavor, err = snapshotFlavor(currentFlavor)
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SilenceUsage = true
+
+		artifacts, err := packager.Build(cmd.Context(), currentFlavor, buildOpts)
+		if err != nil {
+			return err
+		}
+
+		for _, artifact := range artifacts {
+			message.Infof("Built %s\n", artifact)
+		}
+		return nil
+	},
+}
+
+// ociCmd represents the oci command
+var ociCmd = &cobra.Command{
+	Use:   "oci flavor",
+	Short: "Publish the Zarf package and bundle for a flavor to the configured OCI registries",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+		currentFlavor, err = snapshotFlavor(currentFlavor)
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SilenceUsage = true
+
+		httpClient, err := utils.NewHTTPClient(utils.MergeHTTPConfig(releaseConfig.HTTP, httpConfig))
+		if err != nil {
+			return err
+		}
+
+		return oci.Publish(cmd.Context(), currentFlavor, publishOpts, oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: plainHTTP})
+	},
+}
+
+// ociPromoteCmd represents the oci-promote command
+var ociPromoteCmd = &cobra.Command{
+	Use:   "oci-promote flavor",
+	Short: "Copy the published Zarf package and bundle for a flavor from one registry to another",
+	Long: "Copy the manifests and blobs of the <version>-<flavor> Zarf package, and bundle when publishBundle is set, between registries " +
+		"without rebuilding them, such as from a staging registry to the production registry once validated, and verify the digests are identical",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SilenceUsage = true
+
+		httpClient, err := utils.NewHTTPClient(utils.MergeHTTPConfig(releaseConfig.HTTP, httpConfig))
+		if err != nil {
+			return err
+		}
+
+		_, err = oci.Promote(cmd.Context(), currentFlavor, ociPromoteOpts, oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: plainHTTP})
+		return err
+	},
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the yaml, tag, release and publish status of every flavor",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SilenceUsage = true
+
+		opts, err := statusOptions(releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		statuses := status.GetStatus(cmd.Context(), releaseConfig, opts)
+
+		if outputFormat == "json" {
+			return status.WriteJSON(os.Stdout, statuses)
+		}
+		return status.WriteTable(os.Stdout, statuses)
+	},
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list [flavor]",
+	Short: "List the released versions of a flavor, or of every flavor",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		flavors := releaseConfig.Flavors
+		if len(args) == 1 {
+			currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+			if err != nil {
+				return err
+			}
+			flavors = []types.Flavor{currentFlavor}
+		}
+
+		var since time.Time
+		if listSince != "" {
+			since, err = time.Parse(time.DateOnly, listSince)
+			if err != nil {
+				return fmt.Errorf("invalid --since date, expected YYYY-MM-DD: %w", err)
+			}
+		}
+
+		rootCmd.SilenceUsage = true
+
+		opts, err := statusOptions(releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		releases, err := status.ListReleases(releaseConfig, flavors, since, opts)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return status.WriteJSON(os.Stdout, releases)
+		}
+		return status.WriteReleasesTable(os.Stdout, releases)
+	},
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff flavor [from] [to]",
+	Short: "Show the image, chart and manifest changes of a flavor between two releases",
+	Long: "Show the image, chart and manifest changes of a flavor between two git revisions. " +
+		"From defaults to the latest release of the flavor and to defaults to HEAD, a flavor version can be used in place of its tag.",
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		if diffOutputFormat != "text" && diffOutputFormat != "json" {
+			return fmt.Errorf("unsupported output format %q, must be text or json", diffOutputFormat)
+		}
+
+		var from, to string
+		if len(args) > 1 {
+			from = args[1]
+		}
+		if len(args) > 2 {
+			to = args[2]
+		}
+
+		rootCmd.SilenceUsage = true
+
+		packageDiff, err := diff.DiffFlavor(currentFlavor, releaseConfig.Flavors, from, to)
+		if err != nil {
+			return err
+		}
+
+		if diffOutputFormat == "json" {
+			return status.WriteJSON(os.Stdout, packageDiff)
+		}
+		return diff.WriteText(os.Stdout, packageDiff)
+	},
+}
+
+// compatCmd represents the compat command
+var compatCmd = &cobra.Command{
+	Use:   "compat flavor [from] [to]",
+	Short: "Check for variable, component and override changes that break upgrades between two releases",
+	Long: "Compare the Zarf variables, constants and components and the bundle override variables of a flavor between two git revisions. " +
+		"From defaults to the latest release of the flavor and to defaults to HEAD, a flavor version can be used in place of its tag.",
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+		if err != nil {
+			return err
+		}
+
+		currentFlavor, err := utils.GetFlavorConfig(args[0], releaseConfig)
+		if err != nil {
+			return err
+		}
+
+		if diffOutputFormat != "text" && diffOutputFormat != "json" {
+			return fmt.Errorf("unsupported output format %q, must be text or json", diffOutputFormat)
+		}
+
+		var level compat.BumpLevel
+		if compatRequireBump != "" {
+			if level, err = compat.ParseBumpLevel(compatRequireBump); err != nil {
+				return err
+			}
+		}
+
+		var from, to string
+		if len(args) > 1 {
+			from = args[1]
+		}
+		if len(args) > 2 {
+			to = args[2]
+		}
+
+		rootCmd.SilenceUsage = true
+
+		report, err := compat.Check(currentFlavor, releaseConfig.Flavors, from, to)
+		if err != nil {
+			return err
+		}
+
+		if diffOutputFormat == "json" {
+			err = status.WriteJSON(os.Stdout, report)
+		} else {
+			err = compat.WriteText(os.Stdout, report)
+		}
+		if err != nil {
+			return err
+		}
+
+		if compatRequireBump != "" {
+			previousVersion, ok := utils.ParseFlavorTag(report.From, currentFlavor.Name, releaseConfig.Flavors)
+			if !ok {
+				return fmt.Errorf("--require-bump needs a %s release tag to compare against, got %s", currentFlavor.Name, report.From)
+			}
+			if err := compat.VerifyBump(report, previousVersion, currentFlavor.Version, level); err != nil {
+				return err
+			}
+		}
+
+		if compatFailOnBreaking && report.Breaking() {
+			return errors.New("breaking changes found")
+		}
+		return nil
+	},
+}
+
+// statusOptions collects the flags shared by the status and list commands
+func statusOptions(releaseConfig types.ReleaseConfig) (status.Options, error) {
+	if outputFormat != "table" && outputFormat != "json" {
+		return status.Options{}, fmt.Errorf("unsupported output format %q, must be table or json", outputFormat)
+	}
+
+	httpClient, err := utils.NewHTTPClient(utils.MergeHTTPConfig(releaseConfig.HTTP, httpConfig))
+	if err != nil {
+		return status.Options{}, err
+	}
+
+	opts := status.Options{HTTPClient: httpClient, PlainHTTP: plainHTTP}
+	opts.Platform, opts.TokenVarName, err = platformFor(platformName)
+	if err != nil {
+		return status.Options{}, err
+	}
+	if platformTokenVarName != "" {
+		opts.TokenVarName = platformTokenVarName
+	}
+
+	return opts, nil
+}
+
+// platformFor returns the platform and its default token variable, detecting the platform from the origin remote when unset
+func platformFor(name string) (platforms.Platform, string, error) {
+	if name == "" {
+		remoteURL, _, err := utils.GetRepoInfo()
+		if err != nil {
+			message.Warnf("Unable to detect the platform, releases will be reported as unknown: %s\n", err)
+			return nil, "", nil
+		}
+
+		switch {
+		case strings.Contains(remoteURL, "github"):
+			name = "github"
+		case strings.Contains(remoteURL, "gitlab"):
+			name = "gitlab"
+		default:
+			name = "none"
+		}
+	}
+
+	switch name {
+	case "github":
+		return github.Platform{}, "GITHUB_TOKEN", nil
+	case "gitlab":
+		return gitlab.Platform{}, "GITLAB_RELEASE_TOKEN", nil
+	case "none":
+		return nil, "", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported platform %q, must be github, gitlab or none", name)
+	}
+}
+
 // releaseCmd represents the release command
 var releaseCmd = &cobra.Command{
 	Use:   "release platform",
@@ -149,8 +860,25 @@ func init() {
 	releaseCmd.AddCommand(gitlabCmd)
 	releaseCmd.AddCommand(githubCmd)
 	releaseCmd.AddCommand(updateYamlCmd)
+	releaseCmd.AddCommand(verifyYamlCmd)
+	releaseCmd.AddCommand(buildCmd)
+	releaseCmd.AddCommand(ociCmd)
+	releaseCmd.AddCommand(ociPromoteCmd)
+	releaseCmd.AddCommand(statusCmd)
+	releaseCmd.AddCommand(listCmd)
+	releaseCmd.AddCommand(diffCmd)
+	releaseCmd.AddCommand(compatCmd)
+	releaseCmd.AddCommand(prCmd)
+	releaseCmd.AddCommand(changelogCmd)
+	releaseCmd.AddCommand(validateCmd)
+	releaseCmd.AddCommand(promoteCmd)
+	releaseCmd.AddCommand(pruneCmd)
 
 	releaseCmd.PersistentFlags().StringVarP(&releaseDir, "dir", "d", ".", "Path to the directory containing the releaser.yaml file")
+	releaseCmd.PersistentFlags().StringVar(&httpConfig.CAFile, "ca-file", "", "Path to a PEM encoded CA bundle to trust in addition to the system roots")
+	releaseCmd.PersistentFlags().BoolVar(&httpConfig.InsecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Skip verification of TLS certificates (not recommended)")
+	releaseCmd.PersistentFlags().StringVar(&httpConfig.Proxy, "proxy", "", "URL of the HTTP(S) proxy to use, defaults to the HTTP_PROXY/HTTPS_PROXY environment variables")
+	releaseCmd.PersistentFlags().StringVar(&httpConfig.NoProxy, "no-proxy", "", "Comma separated list of hosts that should bypass the proxy")
 
 	checkCmd.Flags().BoolVarP(&checkBoolOutput, "boolean", "b", false, "Switch the output string to a true/false based on if a release is necessary. True if a release is necessary, false if not.")
 
@@ -158,4 +886,86 @@ func init() {
 
 	gitlabCmd.Flags().StringVarP(&gitlabTokenVarName, "token-var-name", "t", "GITLAB_RELEASE_TOKEN", "Environment variable name for GitLab token")
 	githubCmd.Flags().StringVarP(&githubTokenVarName, "token-var-name", "t", "GITHUB_TOKEN", "Environment variable name for GitHub token")
+
+	updateYamlCmd.Flags().BoolVar(&updateYamlCheck, "check", false, "Only verify the version fields match the flavor, exiting with an error and the differences if they do not")
+	updateYamlCmd.Flags().BoolVar(&updateYamlCommit, "commit", false, "Commit the updated zarf.yaml and uds-bundle.yaml files, leaving any other changes uncommitted")
+	updateYamlCmd.Flags().BoolVar(&updateYamlChangelog, "changelog", false, "Add the changes since the previous release of the flavor to its changelog, which is included when committing")
+	updateYamlCmd.Flags().BoolVar(&commitOpts.Push, "push", false, "Commit the updated files and push the commit to the current branch on origin using the platform token")
+	updateYamlCmd.Flags().StringVar(&commitOpts.Message, "commit-message", version.DefaultCommitMessage, "Go template for the commit message with the fields Package, Flavor, Version and Tag")
+	updateYamlCmd.Flags().BoolVar(&commitSign, "sign", false, "Sign the commit with the OpenPGP key in the signing key environment variable")
+	updateYamlCmd.Flags().StringVar(&signingKeyVarName, "signing-key-var-name", "UDS_PK_SIGNING_KEY", "Environment variable name for the ASCII armored OpenPGP private key, its passphrase is read from <name>_PASSPHRASE")
+	updateYamlCmd.Flags().StringVarP(&platformTokenVarName, "token-var-name", "t", "", "Environment variable name for the platform token used to push, defaults to GITHUB_TOKEN or GITLAB_RELEASE_TOKEN")
+
+	prCmd.Flags().StringVar(&prOpts.Version, "version", "", "Version to release, defaults to the current version bumped by --bump when it is already tagged")
+	prCmd.Flags().StringVar(&prOpts.Bump, "bump", "uds", "Part of the version to bump, one of major, minor, patch, uds or rc")
+	prCmd.Flags().StringVar(&prOpts.Title, "title", version.DefaultCommitMessage, "Go template for the pull request title and commit message with the fields Package, Flavor, Version and Tag")
+	prCmd.Flags().BoolVar(&prOpts.SkipChangelog, "skip-changelog", false, "Do not add the changes since the previous release of the flavor to its changelog in the release commit")
+	prCmd.Flags().StringVar(&platformName, "platform", "", "Platform to open the pull request on (github or gitlab), detected from the origin remote by default")
+	prCmd.Flags().StringVarP(&platformTokenVarName, "token-var-name", "t", "", "Environment variable name for the platform token, defaults to GITHUB_TOKEN or GITLAB_RELEASE_TOKEN")
+	prCmd.Flags().BoolVar(&commitSign, "sign", false, "Sign the commit with the OpenPGP key in the signing key environment variable")
+	prCmd.Flags().StringVar(&signingKeyVarName, "signing-key-var-name", "UDS_PK_SIGNING_KEY", "Environment variable name for the ASCII armored OpenPGP private key, its passphrase is read from <name>_PASSPHRASE")
+
+	promoteCmd.Flags().BoolVar(&promoteOpts.SkipOCI, "skip-oci", false, "Do not tag the published package and bundles of the release candidate with the final version")
+	promoteCmd.Flags().BoolVar(&plainHTTP, "plain-http", false, "Use plain HTTP when connecting to the registry")
+	promoteCmd.Flags().StringVar(&platformName, "platform", "", "Platform to release on (github or gitlab), detected from the origin remote by default")
+	promoteCmd.Flags().StringVarP(&platformTokenVarName, "token-var-name", "t", "", "Environment variable name for the platform token, defaults to GITHUB_TOKEN or GITLAB_RELEASE_TOKEN")
+	promoteCmd.Flags().BoolVar(&promoteCommit, "commit", false, "Commit the updated releaser.yaml, zarf.yaml and uds-bundle.yaml files, leaving any other changes uncommitted")
+	promoteCmd.Flags().BoolVar(&commitOpts.Push, "push", false, "Commit the updated files and push the commit to the current branch on origin using the platform token")
+	promoteCmd.Flags().StringVar(&commitOpts.Message, "commit-message", version.DefaultCommitMessage, "Go template for the commit message with the fields Package, Flavor, Version and Tag")
+	promoteCmd.Flags().BoolVar(&commitSign, "sign", false, "Sign the commit with the OpenPGP key in the signing key environment variable")
+	promoteCmd.Flags().StringVar(&signingKeyVarName, "signing-key-var-name", "UDS_PK_SIGNING_KEY", "Environment variable name for the ASCII armored OpenPGP private key, its passphrase is read from <name>_PASSPHRASE")
+
+	pruneCmd.Flags().BoolVar(&pruneOpts.DryRun, "dry-run", false, "List the tags, releases and published artifacts that would be deleted without deleting them")
+	pruneCmd.Flags().BoolVar(&pruneOpts.SkipOCI, "skip-oci", false, "Do not delete the published packages and bundles of the pruned versions")
+	pruneCmd.Flags().BoolVar(&plainHTTP, "plain-http", false, "Use plain HTTP when connecting to the registry")
+	pruneCmd.Flags().StringVar(&platformName, "platform", "", "Platform to delete tags and releases on (github, gitlab or none), detected from the origin remote by default")
+	pruneCmd.Flags().StringVarP(&platformTokenVarName, "token-var-name", "t", "", "Environment variable name for the platform token, defaults to GITHUB_TOKEN or GITLAB_RELEASE_TOKEN")
+
+	buildCmd.Flags().StringVarP(&buildOpts.OutputDir, "output-dir", "o", ".", "Path to the directory the Zarf package is written to")
+	buildCmd.Flags().StringVar(&buildOpts.BundleDir, "bundle-dir", "", "Path to the directory containing the uds-bundle.yaml, defaults to the flavor's bundleFiles. Bundles are written next to their uds-bundle.yaml")
+	buildCmd.Flags().StringVarP(&buildOpts.Arch, "architecture", "a", zarfConfig.GetArch(), "Architecture to build the package and bundle for")
+	buildCmd.Flags().BoolVar(&buildOpts.BuildBundle, "bundle", false, "Also build the bundle after the package")
+	buildCmd.Flags().BoolVar(&buildOpts.SkipSBOM, "skip-sbom", false, "Skip generating SBOMs for the package")
+
+	ociCmd.Flags().StringVar(&publishOpts.PackageDir, "package-dir", ".", "Path to the directory containing the built Zarf package")
+	ociCmd.Flags().StringVar(&publishOpts.BundleDir, "bundle-dir", "", "Path to the directory containing the uds-bundle.yaml and built bundle, defaults to the flavor's bundleFiles")
+	ociCmd.Flags().StringVarP(&publishOpts.Arch, "architecture", "a", zarfConfig.GetArch(), "Architecture of the built package and bundle")
+	ociCmd.Flags().BoolVar(&plainHTTP, "plain-http", false, "Use plain HTTP when connecting to the registry")
+
+	ociPromoteCmd.Flags().StringVar(&ociPromoteOpts.From, "from", "", "Registry URL to copy the package from, defaults to the flavor's publishPackageUrl")
+	ociPromoteCmd.Flags().StringVar(&ociPromoteOpts.To, "to", "", "Registry URL to copy the package to")
+	ociPromoteCmd.Flags().StringVar(&ociPromoteOpts.BundleFrom, "bundle-from", "", "Registry URL to copy the bundle from, defaults to --from when it is set and otherwise to the flavor's publishBundleUrl")
+	ociPromoteCmd.Flags().StringVar(&ociPromoteOpts.BundleTo, "bundle-to", "", "Registry URL to copy the bundle to, defaults to --to")
+	ociPromoteCmd.Flags().BoolVar(&plainHTTP, "plain-http", false, "Use plain HTTP when connecting to the registries")
+	_ = ociPromoteCmd.MarkFlagRequired("to")
+
+	for _, cmd := range []*cobra.Command{statusCmd, listCmd} {
+		cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format, one of table or json")
+		cmd.Flags().StringVar(&platformName, "platform", "", "Platform to look up releases on (github, gitlab or none), detected from the origin remote by default")
+		cmd.Flags().StringVarP(&platformTokenVarName, "token-var-name", "t", "", "Environment variable name for the platform token, defaults to GITHUB_TOKEN or GITLAB_RELEASE_TOKEN")
+	}
+	statusCmd.Flags().BoolVar(&plainHTTP, "plain-http", false, "Use plain HTTP when connecting to the registry")
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only list releases committed on or after this date (YYYY-MM-DD)")
+
+	for _, cmd := range []*cobra.Command{diffCmd, compatCmd} {
+		cmd.Flags().StringVarP(&diffOutputFormat, "output", "o", "text", "Output format, one of text or json")
+	}
+	compatCmd.Flags().BoolVar(&compatFailOnBreaking, "fail-on-breaking", false, "Exit with an error when breaking changes are found")
+	compatCmd.Flags().StringVar(&compatRequireBump, "require-bump", "", "Exit with an error when breaking changes are found and the flavor version does not bump at least this level (major, uds)")
+
+	for _, cmd := range []*cobra.Command{checkCmd, gitlabCmd, githubCmd} {
+		cmd.Flags().BoolVar(&verifyPublished, "verify-published", false, "Verify that the package (and bundle if publishBundle is set) is published with the <version>-<flavor> tag")
+		cmd.Flags().BoolVar(&plainHTTP, "plain-http", false, "Use plain HTTP when connecting to the registry")
+	}
+	for _, cmd := range []*cobra.Command{gitlabCmd, githubCmd} {
+		cmd.Flags().StringSliceVar(&skipChecks, "skip-check", nil, "Preflight checks from the releaser.yaml to skip (clean-worktree, branch, up-to-date, yaml-versions, version-increment or all)")
+	}
+
+	for _, cmd := range []*cobra.Command{showCmd, updateYamlCmd, buildCmd, ociCmd} {
+		cmd.Flags().BoolVar(&snapshot, "snapshot", false, "Use the <version>-<flavor>-dev.<date>.<shortsha> snapshot of HEAD instead of the flavor's version")
+	}
+	for _, cmd := range []*cobra.Command{gitlabCmd, githubCmd} {
+		cmd.Flags().BoolVar(&snapshot, "snapshot", false, "Release a snapshot of HEAD as the rolling nightly-<flavor> prerelease instead of tagging the flavor's version")
+		cmd.Flags().StringSliceVar(&snapshotAssets, "asset", nil, "Files to upload to the nightly release with --snapshot, replacing its previous assets")
+	}
 }
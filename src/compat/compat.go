@@ -0,0 +1,325 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package compat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/diff"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// Severity is how likely an issue is to break an upgrade
+type Severity string
+
+const (
+	// Breaking issues fail deployments that worked with the previous release, such as a removed variable
+	Breaking Severity = "breaking"
+	// Warning issues change behavior on upgrade without failing, such as a changed default
+	Warning Severity = "warning"
+)
+
+// Issue is a single configuration change between two releases that users need to know about
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Change   string   `json:"change"`
+	From     string   `json:"from,omitempty"`
+	To       string   `json:"to,omitempty"`
+}
+
+func (i Issue) String() string {
+	switch i.Change {
+	case "removed":
+		return fmt.Sprintf("%s %s removed", i.Kind, i.Name)
+	case "renamed":
+		return fmt.Sprintf("%s %s renamed to %s", i.Kind, i.Name, i.To)
+	default:
+		return fmt.Sprintf("%s %s %s from %q to %q", i.Kind, i.Name, i.Change, i.From, i.To)
+	}
+}
+
+// Report holds the compatibility issues of a flavor between two revisions
+type Report struct {
+	Flavor string  `json:"flavor"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Issues []Issue `json:"issues"`
+}
+
+// Breaking reports whether any of the issues will break an upgrade
+func (r Report) Breaking() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Check compares the Zarf variables, constants and components and the bundle override variables of the flavor at
+// two revisions. An empty from defaults to the latest tag of the flavor and an empty to defaults to HEAD.
+func Check(flavor types.Flavor, flavors []types.Flavor, from string, to string) (Report, error) {
+	from, to, err := diff.ResolveRange(flavor, flavors, from, to)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Flavor: flavor.Name, From: from, To: to, Issues: []Issue{}}
+
+	var fromPackage, toPackage zarf.ZarfPackage
+	if err := utils.LoadYamlAtRevision(from, "zarf.yaml", &fromPackage); err != nil {
+		return Report{}, err
+	}
+	if err := utils.LoadYamlAtRevision(to, "zarf.yaml", &toPackage); err != nil {
+		return Report{}, err
+	}
+
+	report.Issues = append(report.Issues, compare("variable", variables(fromPackage), variables(toPackage))...)
+	report.Issues = append(report.Issues, compare("constant", constants(fromPackage), constants(toPackage))...)
+
+	fromComponents, err := diff.LoadFlavorComponents(from, flavor.Name)
+	if err != nil {
+		return Report{}, err
+	}
+	toComponents, err := diff.LoadFlavorComponents(to, flavor.Name)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Issues = append(report.Issues, compare("component", components(fromComponents), components(toComponents))...)
+
+	for _, bundlePath := range utils.GetBundleFiles(flavor, "") {
+		fromOverrides, err := overrides(from, bundlePath)
+		if err != nil {
+			return Report{}, err
+		}
+		toOverrides, err := overrides(to, bundlePath)
+		if err != nil {
+			return Report{}, err
+		}
+		report.Issues = append(report.Issues, compare("override", fromOverrides, toOverrides)...)
+	}
+
+	return report, nil
+}
+
+// BumpLevel is the smallest version bump that VerifyBump accepts for breaking changes
+type BumpLevel string
+
+const (
+	// BumpMajor requires the major version to be bumped
+	BumpMajor BumpLevel = "major"
+	// BumpUds also accepts a bump of the uds revision of the same upstream version, such as 1.0.0-uds.0 to
+	// 1.0.0-uds.1, for packages whose configuration changes independently of the upstream version
+	BumpUds BumpLevel = "uds"
+)
+
+// ParseBumpLevel returns the bump level for its name
+func ParseBumpLevel(name string) (BumpLevel, error) {
+	switch level := BumpLevel(name); level {
+	case BumpMajor, BumpUds:
+		return level, nil
+	default:
+		return "", fmt.Errorf("unsupported bump level %q, must be major or uds", name)
+	}
+}
+
+// VerifyBump returns an error when the report has breaking changes but the flavor version was not bumped at least at
+// the given level from the version it was compared against
+func VerifyBump(report Report, previousVersion string, version string, level BumpLevel) error {
+	if !report.Breaking() {
+		return nil
+	}
+
+	previous, err := semver.NewVersion(previousVersion)
+	if err != nil {
+		return fmt.Errorf("unable to parse previous version %s: %w", previousVersion, err)
+	}
+	current, err := semver.NewVersion(version)
+	if err != nil {
+		return fmt.Errorf("unable to parse version %s: %w", version, err)
+	}
+
+	if current.Major() > previous.Major() {
+		return nil
+	}
+	if level == BumpUds && sameUpstream(previous, current) && udsRevision(current) > udsRevision(previous) {
+		return nil
+	}
+
+	required := "a major version bump"
+	if level == BumpUds {
+		required = "a major or uds version bump"
+	}
+	return fmt.Errorf("breaking changes since %s require %s, %s only bumps %s", previousVersion, required, version, bumpType(previous, current))
+}
+
+func bumpType(previous *semver.Version, current *semver.Version) string {
+	switch {
+	case current.Minor() != previous.Minor():
+		return "the minor version"
+	case current.Patch() != previous.Patch():
+		return "the patch version"
+	default:
+		return "the uds version"
+	}
+}
+
+func sameUpstream(previous *semver.Version, current *semver.Version) bool {
+	return current.Major() == previous.Major() && current.Minor() == previous.Minor() && current.Patch() == previous.Patch()
+}
+
+// udsRevision returns N of a uds.N prerelease, or -1 when the version has no uds revision
+func udsRevision(version *semver.Version) int {
+	parts := strings.Split(version.Prerelease(), ".")
+	if len(parts) < 2 || parts[0] != "uds" {
+		return -1
+	}
+	revision, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return -1
+	}
+	return revision
+}
+
+// setting is the comparable state of a variable, constant, component or override. Settings with the same non-empty
+// signature that were removed and added under a different name are reported as renamed.
+type setting struct {
+	value     string
+	signature string
+	changed   string
+}
+
+func variables(zarfPackage zarf.ZarfPackage) map[string]setting {
+	settings := map[string]setting{}
+	for _, variable := range zarfPackage.Variables {
+		signature := ""
+		if variable.Description != "" {
+			signature = variable.Description + "|" + variable.Default
+		}
+		settings[variable.Name] = setting{value: variable.Default, signature: signature, changed: "default changed"}
+	}
+	return settings
+}
+
+func constants(zarfPackage zarf.ZarfPackage) map[string]setting {
+	settings := map[string]setting{}
+	for _, constant := range zarfPackage.Constants {
+		signature := ""
+		if constant.Description != "" {
+			signature = constant.Description + "|" + constant.Value
+		}
+		settings[constant.Name] = setting{value: constant.Value, signature: signature, changed: "value changed"}
+	}
+	return settings
+}
+
+func components(zarfComponents []zarf.ZarfComponent) map[string]setting {
+	settings := map[string]setting{}
+	for _, component := range zarfComponents {
+		settings[component.Name] = setting{signature: component.Description}
+	}
+	return settings
+}
+
+func overrides(revision string, bundlePath string) (map[string]setting, error) {
+	var bundle uds.UDSBundle
+	err := utils.LoadYamlAtRevision(revision, bundlePath, &bundle)
+	// Bundles that do not exist at a revision have no overrides to compare
+	if errors.Is(err, utils.ErrFileNotFound) {
+		return map[string]setting{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Users set override variables by package and variable name, so moving one between charts is not a change
+	settings := map[string]setting{}
+	for _, bundledPackage := range bundle.Packages {
+		for _, charts := range bundledPackage.Overrides {
+			for chartName, chartOverrides := range charts {
+				for _, variable := range chartOverrides.Variables {
+					name := bundledPackage.Name + "/" + variable.Name
+					value := ""
+					if variable.Default != nil {
+						value = fmt.Sprint(variable.Default)
+					}
+					settings[name] = setting{value: value, signature: chartName + "|" + variable.Path, changed: "default changed"}
+				}
+			}
+		}
+	}
+	return settings, nil
+}
+
+func compare(kind string, from map[string]setting, to map[string]setting) []Issue {
+	var issues []Issue
+
+	var added []string
+	for name := range to {
+		if _, found := from[name]; !found {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+
+	var names []string
+	for name := range from {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fromSetting := from[name]
+		toSetting, found := to[name]
+		if found {
+			if fromSetting.value != toSetting.value {
+				issues = append(issues, Issue{Severity: Warning, Kind: kind, Name: name, Change: fromSetting.changed, From: fromSetting.value, To: toSetting.value})
+			}
+			continue
+		}
+
+		issue := Issue{Severity: Breaking, Kind: kind, Name: name, Change: "removed"}
+		for i, addedName := range added {
+			if fromSetting.signature != "" && to[addedName].signature == fromSetting.signature {
+				issue.Change = "renamed"
+				issue.To = addedName
+				added = append(added[:i], added[i+1:]...)
+				break
+			}
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// WriteText renders the report as one line per issue
+func WriteText(w io.Writer, report Report) error {
+	if _, err := fmt.Fprintf(w, "Compatibility of %s from %s to %s\n\n", report.Flavor, report.From, report.To); err != nil {
+		return err
+	}
+
+	if len(report.Issues) == 0 {
+		_, err := fmt.Fprintln(w, "No variable, constant, component or override changes")
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		if _, err := fmt.Fprintf(w, "  %-8s  %s\n", issue.Severity, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package compat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+const fromZarfYaml = `kind: ZarfPackageConfig
+metadata:
+  name: podinfo
+variables:
+  - name: DOMAIN
+    description: The domain to expose podinfo on
+    default: uds.dev
+  - name: REPLICAS
+    description: Number of replicas
+    default: "1"
+  - name: LOG_LEVEL
+    default: info
+constants:
+  - name: UI_COLOR
+    value: blue
+components:
+  - name: podinfo
+    description: Deploys podinfo
+  - name: podinfo-extras
+`
+
+const toZarfYaml = `kind: ZarfPackageConfig
+metadata:
+  name: podinfo
+variables:
+  - name: PODINFO_DOMAIN
+    description: The domain to expose podinfo on
+    default: uds.dev
+  - name: REPLICAS
+    description: Number of replicas
+    default: "2"
+constants:
+  - name: UI_COLOR
+    value: blue
+components:
+  - name: podinfo-app
+    description: Deploys podinfo
+  - name: podinfo-extras
+`
+
+const fromBundleYaml = `kind: UDSBundle
+metadata:
+  name: podinfo-bundle
+packages:
+  - name: podinfo
+    path: ../
+    ref: 6.4.0-uds.0
+    overrides:
+      podinfo:
+        podinfo:
+          variables:
+            - name: UI_MESSAGE
+              path: ui.message
+              default: hello
+            - name: UI_LOGO
+              path: ui.logo
+`
+
+const toBundleYaml = `kind: UDSBundle
+metadata:
+  name: podinfo-bundle
+packages:
+  - name: podinfo
+    path: ../
+    ref: 6.5.0-uds.0
+    overrides:
+      podinfo-app:
+        podinfo:
+          variables:
+            - name: UI_MESSAGE
+              path: ui.message
+              default: hello
+`
+
+func commitFiles(t *testing.T, repo *git.Repository, files map[string]string, tag string) {
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for name, contents := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(contents), 0o644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+
+	hash, err := worktree.Commit("update", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+
+	if tag != "" {
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	repo := testutil.InitRepo(t)
+
+	commitFiles(t, repo, map[string]string{"zarf.yaml": fromZarfYaml, "bundle/uds-bundle.yaml": fromBundleYaml}, "6.4.0-uds.0-upstream")
+	commitFiles(t, repo, map[string]string{"zarf.yaml": toZarfYaml, "bundle/uds-bundle.yaml": toBundleYaml}, "")
+
+	flavor := types.Flavor{Name: "upstream", Version: "6.5.0-uds.0"}
+	report, err := Check(flavor, []types.Flavor{flavor}, "", "")
+	require.NoError(t, err)
+	require.Equal(t, "6.4.0-uds.0-upstream", report.From)
+	require.Equal(t, "HEAD", report.To)
+	require.True(t, report.Breaking())
+
+	require.Equal(t, []Issue{
+		{Severity: Breaking, Kind: "variable", Name: "DOMAIN", Change: "renamed", To: "PODINFO_DOMAIN"},
+		{Severity: Breaking, Kind: "variable", Name: "LOG_LEVEL", Change: "removed"},
+		{Severity: Warning, Kind: "variable", Name: "REPLICAS", Change: "default changed", From: "1", To: "2"},
+		{Severity: Breaking, Kind: "component", Name: "podinfo", Change: "renamed", To: "podinfo-app"},
+		{Severity: Breaking, Kind: "override", Name: "podinfo/UI_LOGO", Change: "removed"},
+	}, report.Issues)
+
+	require.ErrorContains(t, VerifyBump(report, "6.4.0-uds.0", "6.5.0-uds.0", BumpMajor), "only bumps the minor version")
+	require.ErrorContains(t, VerifyBump(report, "6.4.0-uds.0", "6.4.0-uds.1", BumpMajor), "only bumps the uds version")
+	require.NoError(t, VerifyBump(report, "6.4.0-uds.0", "7.0.0-uds.0", BumpMajor))
+
+	// A uds bump only counts when the upstream version stays the same
+	require.NoError(t, VerifyBump(report, "6.4.0-uds.0", "6.4.0-uds.1", BumpUds))
+	require.NoError(t, VerifyBump(report, "6.4.0-uds.0", "7.0.0-uds.0", BumpUds))
+	require.ErrorContains(t, VerifyBump(report, "6.4.0-uds.0", "6.5.0-uds.0", BumpUds), "require a major or uds version bump, 6.5.0-uds.0 only bumps the minor version")
+	require.ErrorContains(t, VerifyBump(report, "6.4.0-uds.1", "6.4.0-uds.1", BumpUds), "only bumps the uds version")
+
+	report, err = Check(flavor, []types.Flavor{flavor}, "HEAD", "HEAD")
+	require.NoError(t, err)
+	require.Empty(t, report.Issues)
+	require.NoError(t, VerifyBump(report, "6.5.0-uds.0", "6.5.0-uds.1", BumpMajor))
+}
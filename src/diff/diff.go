@@ -0,0 +1,271 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// Change is an item that was added, removed or changed between two revisions.
+// From is empty for added items and To is empty for removed items.
+type Change struct {
+	Name    string `json:"name"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	FromURL string `json:"fromUrl,omitempty"`
+	ToURL   string `json:"toUrl,omitempty"`
+}
+
+// Added reports whether the item only exists at the newer revision
+func (c Change) Added() bool {
+	return c.From == "" && c.FromURL == ""
+}
+
+// Removed reports whether the item only exists at the older revision
+func (c Change) Removed() bool {
+	return c.To == "" && c.ToURL == ""
+}
+
+// PackageDiff holds the image, chart and manifest changes of a flavor between two revisions
+type PackageDiff struct {
+	Flavor    string   `json:"flavor"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Images    []Change `json:"images"`
+	Charts    []Change `json:"charts"`
+	Manifests []Change `json:"manifests"`
+}
+
+// Empty reports whether nothing changed between the two revisions
+func (d PackageDiff) Empty() bool {
+	return len(d.Images) == 0 && len(d.Charts) == 0 && len(d.Manifests) == 0
+}
+
+// DiffFlavor compares the flavor's components in the zarf.yaml at two revisions. An empty from defaults to the
+// latest tag of the flavor and an empty to defaults to HEAD.
+func DiffFlavor(flavor types.Flavor, flavors []types.Flavor, from string, to string) (PackageDiff, error) {
+	from, to, err := ResolveRange(flavor, flavors, from, to)
+	if err != nil {
+		return PackageDiff{}, err
+	}
+
+	fromComponents, err := LoadFlavorComponents(from, flavor.Name)
+	if err != nil {
+		return PackageDiff{}, err
+	}
+
+	toComponents, err := LoadFlavorComponents(to, flavor.Name)
+	if err != nil {
+		return PackageDiff{}, err
+	}
+
+	return PackageDiff{
+		Flavor:    flavor.Name,
+		From:      from,
+		To:        to,
+		Images:    diffItems(images(fromComponents), images(toComponents)),
+		Charts:    diffItems(charts(fromComponents), charts(toComponents)),
+		Manifests: diffItems(manifests(fromComponents), manifests(toComponents)),
+	}, nil
+}
+
+// ResolveRange fills in the default revisions of a diff. A revision that is not a git revision itself is also tried
+// as a flavor version, so 1.0.0-uds.0 can be used for the 1.0.0-uds.0-<flavor> tag.
+func ResolveRange(flavor types.Flavor, flavors []types.Flavor, from string, to string) (string, string, error) {
+	if from == "" {
+		latestTag, err := utils.GetLatestFlavorTag(flavor.Name, flavors)
+		if err != nil {
+			return "", "", err
+		}
+		if latestTag == nil {
+			return "", "", fmt.Errorf("flavor %s has no previous release to compare against", flavor.Name)
+		}
+		from = latestTag.Name
+	}
+
+	if to == "" {
+		to = "HEAD"
+	}
+
+	from, err := resolveFlavorRevision(from, flavor.Name)
+	if err != nil {
+		return "", "", err
+	}
+
+	to, err = resolveFlavorRevision(to, flavor.Name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return from, to, nil
+}
+
+func resolveFlavorRevision(revision string, flavor string) (string, error) {
+	if _, err := utils.GetRevisionCommit(revision); err == nil {
+		return revision, nil
+	}
+
+	flavorTag := fmt.Sprintf("%s-%s", revision, flavor)
+	if _, err := utils.GetRevisionCommit(flavorTag); err != nil {
+		return "", fmt.Errorf("unable to resolve %s or %s", revision, flavorTag)
+	}
+
+	return flavorTag, nil
+}
+
+// LoadFlavorComponents returns the components of the zarf.yaml at a revision that are included when creating the
+// package for the flavor, following local component imports
+func LoadFlavorComponents(revision string, flavor string) ([]zarf.ZarfComponent, error) {
+	return loadComponents(revision, "zarf.yaml", flavor, nil)
+}
+
+func loadComponents(revision string, zarfPath string, flavor string, names []string) ([]zarf.ZarfComponent, error) {
+	var zarfPackage zarf.ZarfPackage
+	err := utils.LoadYamlAtRevision(revision, zarfPath, &zarfPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []zarf.ZarfComponent
+	for _, component := range zarfPackage.Components {
+		if component.Only.Flavor != "" && component.Only.Flavor != flavor {
+			continue
+		}
+		if names != nil && !slices.Contains(names, component.Name) {
+			continue
+		}
+
+		// Imported components contribute the images, charts and manifests of the component they import
+		if component.Import.Path != "" {
+			importName := component.Import.Name
+			if importName == "" {
+				importName = component.Name
+			}
+
+			imported, err := loadComponents(revision, path.Join(path.Dir(zarfPath), component.Import.Path, "zarf.yaml"), flavor, []string{importName})
+			if errors.Is(err, utils.ErrFileNotFound) {
+				return nil, fmt.Errorf("component %s imports a package that does not exist: %w", component.Name, err)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			for _, importedComponent := range imported {
+				component.Images = append(component.Images, importedComponent.Images...)
+				component.Charts = append(component.Charts, importedComponent.Charts...)
+				component.Manifests = append(component.Manifests, importedComponent.Manifests...)
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// item is the comparable value of an image, chart or manifest keyed by its identity
+type item struct {
+	name  string
+	value string
+	url   string
+}
+
+func images(components []zarf.ZarfComponent) map[string]item {
+	tags := map[string][]string{}
+	for _, component := range components {
+		for _, image := range component.Images {
+			repository, tag := SplitImage(image)
+			if !slices.Contains(tags[repository], tag) {
+				tags[repository] = append(tags[repository], tag)
+			}
+		}
+	}
+
+	items := map[string]item{}
+	for repository, repositoryTags := range tags {
+		sort.Strings(repositoryTags)
+		items[repository] = item{name: repository, value: strings.Join(repositoryTags, ", ")}
+	}
+	return items
+}
+
+func charts(components []zarf.ZarfComponent) map[string]item {
+	items := map[string]item{}
+	for _, component := range components {
+		for _, chart := range component.Charts {
+			url := chart.URL
+			if url == "" {
+				url = chart.LocalPath
+			}
+			items[component.Name+"/"+chart.Name] = item{name: chart.Name, value: chart.Version, url: url}
+		}
+	}
+	return items
+}
+
+func manifests(components []zarf.ZarfComponent) map[string]item {
+	items := map[string]item{}
+	for _, component := range components {
+		for _, manifest := range component.Manifests {
+			sources := append(append([]string{}, manifest.Files...), manifest.Kustomizations...)
+			items[component.Name+"/"+manifest.Name] = item{name: manifest.Name, value: strings.Join(sources, ", ")}
+		}
+	}
+	return items
+}
+
+func diffItems(from map[string]item, to map[string]item) []Change {
+	changes := []Change{}
+
+	for key, toItem := range to {
+		fromItem, found := from[key]
+		switch {
+		case !found:
+			changes = append(changes, Change{Name: toItem.name, To: toItem.value, ToURL: toItem.url})
+		case fromItem.value != toItem.value || fromItem.url != toItem.url:
+			changes = append(changes, Change{Name: toItem.name, From: fromItem.value, To: toItem.value, FromURL: fromItem.url, ToURL: toItem.url})
+		}
+	}
+
+	for key, fromItem := range from {
+		if _, found := to[key]; !found {
+			changes = append(changes, Change{Name: fromItem.name, From: fromItem.value, FromURL: fromItem.url})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].From+changes[i].To < changes[j].From+changes[j].To
+	})
+
+	return changes
+}
+
+// SplitImage splits an image reference into its repository and its tag and/or digest
+func SplitImage(image string) (repository string, tag string) {
+	if at := strings.Index(image, "@"); at >= 0 {
+		repository, tag = image[:at], image[at:]
+		if colon := strings.LastIndex(repository, ":"); colon > strings.LastIndex(repository, "/") {
+			return repository[:colon], repository[colon+1:] + tag
+		}
+		return repository, tag
+	}
+
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		return image[:colon], image[colon+1:]
+	}
+
+	return image, "latest"
+}
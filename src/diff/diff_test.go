@@ -0,0 +1,219 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+const fromZarfYaml = `kind: ZarfPackageConfig
+metadata:
+  name: podinfo
+  version: 6.4.0-uds.0
+components:
+  - name: podinfo
+    only:
+      flavor: upstream
+    charts:
+      - name: podinfo
+        version: 6.4.0
+        url: oci://ghcr.io/stefanprodan/charts/podinfo
+    images:
+      - ghcr.io/stefanprodan/podinfo:6.4.0
+      - docker.io/library/busybox:1.36
+  - name: podinfo
+    only:
+      flavor: registry1
+    images:
+      - registry1.dso.mil/ironbank/podinfo:6.4.0
+  - name: config
+    import:
+      path: common
+    manifests:
+      - name: network-policies
+        files:
+          - manifests/allow.yaml
+`
+
+const fromCommonZarfYaml = `kind: ZarfPackageConfig
+metadata:
+  name: podinfo-common
+components:
+  - name: config
+    charts:
+      - name: uds-podinfo-config
+        version: 0.1.0
+        localPath: ../chart
+`
+
+const toZarfYaml = `kind: ZarfPackageConfig
+metadata:
+  name: podinfo
+  version: 6.5.0-uds.0
+components:
+  - name: podinfo
+    only:
+      flavor: upstream
+    charts:
+      - name: podinfo
+        version: 6.5.0
+        url: oci://ghcr.io/stefanprodan/charts/podinfo
+    images:
+      - ghcr.io/stefanprodan/podinfo:6.5.0
+      - ghcr.io/stefanprodan/podinfo-sidecar:1.0.0@sha256:abc
+  - name: podinfo
+    only:
+      flavor: registry1
+    images:
+      - registry1.dso.mil/ironbank/podinfo:6.5.0
+  - name: config
+    import:
+      path: common
+    manifests:
+      - name: network-policies
+        files:
+          - manifests/allow.yaml
+          - manifests/deny.yaml
+`
+
+func commitFiles(t *testing.T, repo *git.Repository, files map[string]string, tag string, when time.Time) {
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for name, contents := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(contents), 0o644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+
+	hash, err := worktree.Commit("update", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: when}})
+	require.NoError(t, err)
+
+	if tag != "" {
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+}
+
+func TestDiffFlavor(t *testing.T) {
+	repo := testutil.InitRepo(t)
+
+	when := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	commitFiles(t, repo, map[string]string{"zarf.yaml": fromZarfYaml, "common/zarf.yaml": fromCommonZarfYaml}, "6.4.0-uds.0-upstream", when)
+	commitFiles(t, repo, map[string]string{"zarf.yaml": toZarfYaml}, "", when.Add(time.Hour))
+
+	flavors := []types.Flavor{{Name: "upstream"}, {Name: "registry1"}}
+
+	packageDiff, err := DiffFlavor(flavors[0], flavors, "", "")
+	require.NoError(t, err)
+	require.Equal(t, "6.4.0-uds.0-upstream", packageDiff.From)
+	require.Equal(t, "HEAD", packageDiff.To)
+
+	require.Equal(t, []Change{
+		{Name: "docker.io/library/busybox", From: "1.36"},
+		{Name: "ghcr.io/stefanprodan/podinfo", From: "6.4.0", To: "6.5.0"},
+		{Name: "ghcr.io/stefanprodan/podinfo-sidecar", To: "1.0.0@sha256:abc"},
+	}, packageDiff.Images)
+	require.Equal(t, []Change{
+		{Name: "podinfo", From: "6.4.0", To: "6.5.0", FromURL: "oci://ghcr.io/stefanprodan/charts/podinfo", ToURL: "oci://ghcr.io/stefanprodan/charts/podinfo"},
+	}, packageDiff.Charts)
+	require.Equal(t, []Change{
+		{Name: "network-policies", From: "manifests/allow.yaml", To: "manifests/allow.yaml, manifests/deny.yaml"},
+	}, packageDiff.Manifests)
+
+	packageDiff, err = DiffFlavor(flavors[1], flavors, "HEAD~1", "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		{Name: "registry1.dso.mil/ironbank/podinfo", From: "6.4.0", To: "6.5.0"},
+	}, packageDiff.Images)
+	require.Empty(t, packageDiff.Charts)
+
+	// Charts from imported components are included
+	components, err := LoadFlavorComponents("HEAD", "registry1")
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+	require.Equal(t, "uds-podinfo-config", components[1].Charts[0].Name)
+
+	// A flavor version can be used in place of its tag
+	packageDiff, err = DiffFlavor(flavors[0], flavors, "6.4.0-uds.0", "6.4.0-uds.0")
+	require.NoError(t, err)
+	require.True(t, packageDiff.Empty())
+
+	var out bytes.Buffer
+	require.NoError(t, WriteText(&out, packageDiff))
+	require.Contains(t, out.String(), "No image, chart or manifest changes")
+
+	_, err = DiffFlavor(flavors[1], flavors, "", "")
+	require.ErrorContains(t, err, "no previous release")
+
+	// Release notes compare the previous release of the flavor with HEAD
+	notes, err := UpstreamChanges(types.Flavor{Name: "upstream", Version: "6.5.0-uds.0"}, flavors)
+	require.NoError(t, err)
+	require.Contains(t, notes, "## Upstream changes\n\nChanges since 6.4.0-uds.0-upstream:\n")
+	require.Contains(t, notes, "- `ghcr.io/stefanprodan/podinfo` `6.4.0` → `6.5.0`\n")
+	require.Contains(t, notes, "- `docker.io/library/busybox` removed (was `1.36`)\n")
+	require.Contains(t, notes, "- [`podinfo`](https://ghcr.io/stefanprodan/charts/podinfo) `6.4.0` → `6.5.0`\n")
+	require.Contains(t, notes, "- `network-policies` changed\n")
+
+	notes, err = UpstreamChanges(types.Flavor{Name: "upstream", Version: "6.4.0-uds.0"}, flavors)
+	require.NoError(t, err)
+	require.Empty(t, notes)
+
+	_, err = DiffFlavor(flavors[0], flavors, "9.9.9", "")
+	require.ErrorContains(t, err, "unable to resolve 9.9.9 or 9.9.9-upstream")
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		image              string
+		expectedRepository string
+		expectedTag        string
+	}{
+		{image: "ghcr.io/stefanprodan/podinfo:6.5.0", expectedRepository: "ghcr.io/stefanprodan/podinfo", expectedTag: "6.5.0"},
+		{image: "localhost:5000/podinfo:6.5.0", expectedRepository: "localhost:5000/podinfo", expectedTag: "6.5.0"},
+		{image: "localhost:5000/podinfo", expectedRepository: "localhost:5000/podinfo", expectedTag: "latest"},
+		{image: "podinfo@sha256:abc", expectedRepository: "podinfo", expectedTag: "@sha256:abc"},
+		{image: "podinfo:6.5.0@sha256:abc", expectedRepository: "podinfo", expectedTag: "6.5.0@sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			repository, tag := SplitImage(tt.image)
+			require.Equal(t, tt.expectedRepository, repository)
+			require.Equal(t, tt.expectedTag, tag)
+		})
+	}
+}
+
+func TestChartLink(t *testing.T) {
+	tests := []struct {
+		url      string
+		version  string
+		expected string
+	}{
+		{url: "oci://ghcr.io/stefanprodan/charts/podinfo", version: "6.5.0", expected: "https://ghcr.io/stefanprodan/charts/podinfo"},
+		{url: "https://stefanprodan.github.io/podinfo", version: "6.5.0", expected: "https://stefanprodan.github.io/podinfo"},
+		{url: "https://github.com/stefanprodan/podinfo.git", version: "6.5.0", expected: "https://github.com/stefanprodan/podinfo/releases/tag/6.5.0"},
+		{url: "https://github.com/stefanprodan/podinfo.git@v6.5.1", version: "6.5.0", expected: "https://github.com/stefanprodan/podinfo/releases/tag/v6.5.1"},
+		{url: "https://gitlab.com/group/podinfo.git", version: "6.5.0", expected: "https://gitlab.com/group/podinfo/-/tags/6.5.0"},
+		{url: "../chart", version: "0.1.0", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			require.Equal(t, tt.expected, ChartLink(tt.url, tt.version))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+)
+
+// WriteText renders the diff as a list of added (+), removed (-) and changed (~) items per section
+func WriteText(w io.Writer, d PackageDiff) error {
+	if _, err := fmt.Fprintf(w, "Changes for %s from %s to %s\n", d.Flavor, d.From, d.To); err != nil {
+		return err
+	}
+
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "\nNo image, chart or manifest changes")
+		return err
+	}
+
+	sections := []struct {
+		title   string
+		changes []Change
+	}{
+		{title: "Images", changes: d.Images},
+		{title: "Charts", changes: d.Charts},
+		{title: "Manifests", changes: d.Manifests},
+	}
+
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "\n%s:\n", section.title); err != nil {
+			return err
+		}
+		for _, change := range section.changes {
+			if _, err := fmt.Fprintf(w, "  %s\n", formatChange(change)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatChange(change Change) string {
+	switch {
+	case change.Added():
+		return fmt.Sprintf("+ %s %s", change.Name, withURL(change.To, change.ToURL))
+	case change.Removed():
+		return fmt.Sprintf("- %s %s", change.Name, withURL(change.From, change.FromURL))
+	case change.FromURL != change.ToURL:
+		return fmt.Sprintf("~ %s %s -> %s", change.Name, withURL(change.From, change.FromURL), withURL(change.To, change.ToURL))
+	default:
+		return fmt.Sprintf("~ %s %s -> %s", change.Name, change.From, withURL(change.To, change.ToURL))
+	}
+}
+
+func withURL(value string, url string) string {
+	if url == "" {
+		return value
+	}
+	if value == "" {
+		return fmt.Sprintf("(%s)", url)
+	}
+	return fmt.Sprintf("%s (%s)", value, url)
+}
+
+// UpstreamChanges returns the "Upstream changes" release notes section comparing the flavor's previous release with
+// HEAD, or an empty string when the flavor has not been released before
+func UpstreamChanges(flavor types.Flavor, flavors []types.Flavor) (string, error) {
+	previousTag, err := utils.GetPreviousFlavorTag(flavor, flavors)
+	if err != nil || previousTag == nil {
+		return "", err
+	}
+
+	packageDiff, err := DiffFlavor(flavor, flavors, previousTag.Name, "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	var notes strings.Builder
+	if err := WriteMarkdown(&notes, packageDiff); err != nil {
+		return "", err
+	}
+	return notes.String(), nil
+}
+
+// WriteMarkdown renders the diff as the "Upstream changes" section of release notes, linking charts to their source
+func WriteMarkdown(w io.Writer, d PackageDiff) error {
+	if _, err := fmt.Fprintf(w, "## Upstream changes\n\nChanges since %s:\n", d.From); err != nil {
+		return err
+	}
+
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "\nNo image, chart or manifest changes")
+		return err
+	}
+
+	if len(d.Images) > 0 {
+		if _, err := fmt.Fprint(w, "\n### Images\n\n"); err != nil {
+			return err
+		}
+		for _, change := range d.Images {
+			if _, err := fmt.Fprintf(w, "- `%s` %s\n", change.Name, markdownVersions(change)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(d.Charts) > 0 {
+		if _, err := fmt.Fprint(w, "\n### Charts\n\n"); err != nil {
+			return err
+		}
+		for _, change := range d.Charts {
+			name := fmt.Sprintf("`%s`", change.Name)
+			url, version := change.ToURL, change.To
+			if change.Removed() {
+				url, version = change.FromURL, change.From
+			}
+			if link := ChartLink(url, version); link != "" {
+				name = fmt.Sprintf("[%s](%s)", name, link)
+			}
+			if _, err := fmt.Fprintf(w, "- %s %s\n", name, markdownVersions(change)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(d.Manifests) > 0 {
+		if _, err := fmt.Fprint(w, "\n### Manifests\n\n"); err != nil {
+			return err
+		}
+		for _, change := range d.Manifests {
+			status := "changed"
+			switch {
+			case change.Added():
+				status = "added"
+			case change.Removed():
+				status = "removed"
+			}
+			if _, err := fmt.Fprintf(w, "- `%s` %s\n", change.Name, status); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func markdownVersions(change Change) string {
+	switch {
+	case change.Added():
+		return fmt.Sprintf("added at `%s`", change.To)
+	case change.Removed():
+		return fmt.Sprintf("removed (was `%s`)", change.From)
+	case change.From == change.To:
+		return fmt.Sprintf("`%s` moved to %s", change.To, change.ToURL)
+	default:
+		return fmt.Sprintf("`%s` → `%s`", change.From, change.To)
+	}
+}
+
+// ChartLink returns a browsable link for a chart URL at a version, or an empty string for local charts
+func ChartLink(chartURL string, version string) string {
+	if strings.HasPrefix(chartURL, "oci://") {
+		return "https://" + strings.TrimPrefix(chartURL, "oci://")
+	}
+
+	// Charts pulled from git use the repo@ref syntax or the version as the tag
+	if at := strings.LastIndex(chartURL, "@"); at > strings.LastIndex(chartURL, "/") {
+		chartURL, version = chartURL[:at], chartURL[at+1:]
+	}
+	chartURL = strings.TrimSuffix(chartURL, ".git")
+
+	switch {
+	case strings.HasPrefix(chartURL, "https://github.com/"):
+		return fmt.Sprintf("%s/releases/tag/%s", chartURL, version)
+	case strings.HasPrefix(chartURL, "https://gitlab.com/"):
+		return fmt.Sprintf("%s/-/tags/%s", chartURL, version)
+	case strings.HasPrefix(chartURL, "https://"), strings.HasPrefix(chartURL, "http://"):
+		return chartURL
+	default:
+		return ""
+	}
+}
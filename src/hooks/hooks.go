@@ -0,0 +1,80 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+)
+
+// Stage is the point in the release process a hook runs at
+type Stage string
+
+const (
+	PreRelease    Stage = "preRelease"
+	PostRelease   Stage = "postRelease"
+	PreUpdateYaml Stage = "preUpdateYaml"
+)
+
+// Metadata about the release that is exported to hooks as UDS_PK_* environment variables
+type Metadata struct {
+	Package    string
+	Flavor     types.Flavor
+	ReleaseURL string
+}
+
+// Commands returns the global commands followed by the flavor's commands for a stage
+func Commands(stage Stage, global types.Hooks, flavor types.Flavor) []string {
+	var commands []string
+	for _, hooks := range []types.Hooks{global, flavor.Hooks} {
+		switch stage {
+		case PreRelease:
+			commands = append(commands, hooks.PreRelease...)
+		case PostRelease:
+			commands = append(commands, hooks.PostRelease...)
+		case PreUpdateYaml:
+			commands = append(commands, hooks.PreUpdateYaml...)
+		}
+	}
+	return commands
+}
+
+// Run executes the global and flavor hooks for a stage in order, stopping at the first command that fails
+func Run(ctx context.Context, stage Stage, global types.Hooks, metadata Metadata) error {
+	commands := Commands(stage, global, metadata.Flavor)
+	if len(commands) == 0 {
+		return nil
+	}
+
+	shell, shellArgs := exec.GetOSShell(zarf.Shell{})
+	config := exec.PrintCfg()
+	config.Env = Env(stage, metadata)
+
+	for _, command := range commands {
+		message.Infof("Running %s hook: %s\n", stage, command)
+
+		_, _, err := exec.CmdWithContext(ctx, config, shell, append(shellArgs, command)...)
+		if err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", stage, command, err)
+		}
+	}
+	return nil
+}
+
+// Env returns the environment variables describing the release that are set for hooks
+func Env(stage Stage, metadata Metadata) []string {
+	return []string{
+		"UDS_PK_HOOK=" + string(stage),
+		"UDS_PK_PACKAGE=" + metadata.Package,
+		"UDS_PK_FLAVOR=" + metadata.Flavor.Name,
+		"UDS_PK_VERSION=" + metadata.Flavor.Version,
+		fmt.Sprintf("UDS_PK_TAG=%s-%s", metadata.Flavor.Version, metadata.Flavor.Name),
+		"UDS_PK_RELEASE_URL=" + metadata.ReleaseURL,
+	}
+}
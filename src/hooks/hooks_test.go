@@ -0,0 +1,48 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands(t *testing.T) {
+	global := types.Hooks{PreRelease: []string{"make docs"}, PostRelease: []string{"./notify-catalog.sh"}}
+	flavor := types.Flavor{Name: "upstream", Hooks: types.Hooks{PreRelease: []string{"make mirror"}, PreUpdateYaml: []string{"make generate"}}}
+
+	require.Equal(t, []string{"make docs", "make mirror"}, Commands(PreRelease, global, flavor))
+	require.Equal(t, []string{"./notify-catalog.sh"}, Commands(PostRelease, global, flavor))
+	require.Equal(t, []string{"make generate"}, Commands(PreUpdateYaml, global, flavor))
+	require.Empty(t, Commands(PreUpdateYaml, types.Hooks{}, types.Flavor{}))
+}
+
+func TestRun(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "hook.out")
+	metadata := Metadata{
+		Package:    "podinfo",
+		Flavor:     types.Flavor{Name: "upstream", Version: "6.5.0-uds.0"},
+		ReleaseURL: "https://example.com/releases/6.5.0-uds.0-upstream",
+	}
+
+	global := types.Hooks{PostRelease: []string{`echo "$UDS_PK_HOOK $UDS_PK_PACKAGE $UDS_PK_TAG" > ` + output}}
+	metadata.Flavor.Hooks = types.Hooks{PostRelease: []string{`echo "$UDS_PK_FLAVOR $UDS_PK_VERSION $UDS_PK_RELEASE_URL" >> ` + output}}
+
+	require.NoError(t, Run(context.Background(), PostRelease, global, metadata))
+
+	contents, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.Equal(t, "postRelease podinfo 6.5.0-uds.0-upstream\nupstream 6.5.0-uds.0 https://example.com/releases/6.5.0-uds.0-upstream\n", string(contents))
+
+	// A failing command stops the hooks that come after it
+	global = types.Hooks{PreRelease: []string{"exit 3", "touch " + output + ".next"}}
+	err = Run(context.Background(), PreRelease, global, metadata)
+	require.ErrorContains(t, err, `preRelease hook "exit 3" failed`)
+	require.NoFileExists(t, output+".next")
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+)
+
+// Placements of the hand-written notes relative to the generated notes
+const (
+	PlacementHeader = "header"
+	PlacementFooter = "footer"
+)
+
+// Validate returns an error when the source or placement of the notes is not known
+func Validate(config types.NotesConfig) error {
+	switch config.Source {
+	case "", SourceCommits, SourcePullRequests:
+	default:
+		return fmt.Errorf("unknown notes source %q, must be %s or %s", config.Source, SourceCommits, SourcePullRequests)
+	}
+
+	switch config.Placement {
+	case "", PlacementHeader, PlacementFooter:
+	default:
+		return fmt.Errorf("unknown notes placement %q, must be %s or %s", config.Placement, PlacementHeader, PlacementFooter)
+	}
+	return nil
+}
+
+// File returns the path of the hand-written notes for the flavor's version, preferring <version>-<flavor>.md over
+// <version>.md, or an empty string when there are none or no notes directory is configured
+func File(config types.NotesConfig, flavor types.Flavor) (string, error) {
+	if config.Directory == "" {
+		return "", nil
+	}
+
+	for _, name := range []string{fmt.Sprintf("%s-%s.md", flavor.Version, flavor.Name), flavor.Version + ".md"} {
+		path := filepath.Join(config.Directory, name)
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// ReadFile returns the hand-written notes for the flavor's version, or an empty string when there are none
+func ReadFile(config types.NotesConfig, flavor types.Flavor) (string, error) {
+	path, err := File(config, flavor)
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(contents)), nil
+}
+
+// MissingUpgradeNotes returns a warning when the flavor's version bumps the major version of its previous release
+// but the notes directory has no hand-written notes for it, or an empty string otherwise
+func MissingUpgradeNotes(config types.NotesConfig, flavor types.Flavor, flavors []types.Flavor) (string, error) {
+	if config.Directory == "" {
+		return "", nil
+	}
+
+	previousTag, err := utils.GetPreviousFlavorTag(flavor, flavors)
+	if err != nil || previousTag == nil {
+		return "", err
+	}
+
+	previous, err := semver.NewVersion(previousTag.Version)
+	if err != nil {
+		return "", nil
+	}
+	current, err := semver.NewVersion(flavor.Version)
+	if err != nil || current.Major() <= previous.Major() {
+		return "", nil
+	}
+
+	path, err := File(config, flavor)
+	if err != nil || path != "" {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s is a major version bump from %s but %s has no %s.md or %s-%s.md with upgrade notes",
+		flavor.Version, flavor.Name, previousTag.Name, config.Directory, flavor.Version, flavor.Version, flavor.Name), nil
+}
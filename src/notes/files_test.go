@@ -0,0 +1,88 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(types.NotesConfig{}))
+	require.NoError(t, Validate(types.NotesConfig{Source: SourcePullRequests, Placement: PlacementFooter}))
+	require.ErrorContains(t, Validate(types.NotesConfig{Source: "issues"}), `unknown notes source "issues"`)
+	require.ErrorContains(t, Validate(types.NotesConfig{Placement: "middle"}), `unknown notes placement "middle"`)
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	config := types.NotesConfig{Directory: dir}
+	flavor := types.Flavor{Name: "upstream", Version: "2.0.0-uds.0"}
+
+	contents, err := ReadFile(config, flavor)
+	require.NoError(t, err)
+	require.Empty(t, contents)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2.0.0-uds.0.md"), []byte("\n## Upgrading\n\nRename DOMAIN.\n\n"), 0o644))
+	contents, err = ReadFile(config, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "## Upgrading\n\nRename DOMAIN.", contents)
+
+	// Notes for the flavor are preferred over notes for every flavor
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2.0.0-uds.0-upstream.md"), []byte("Upstream only"), 0o644))
+	path, err := File(config, flavor)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "2.0.0-uds.0-upstream.md"), path)
+	contents, err = ReadFile(config, types.Flavor{Name: "registry1", Version: "2.0.0-uds.0"})
+	require.NoError(t, err)
+	require.Equal(t, "## Upgrading\n\nRename DOMAIN.", contents)
+
+	// Nothing is read without a notes directory
+	contents, err = ReadFile(types.NotesConfig{}, flavor)
+	require.NoError(t, err)
+	require.Empty(t, contents)
+}
+
+func TestMissingUpgradeNotes(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("README.md", []byte("readme"), 0o644))
+	_, err = worktree.Add("README.md")
+	require.NoError(t, err)
+	hash, err := worktree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	_, err = repo.CreateTag("1.2.0-uds.3-upstream", hash, nil)
+	require.NoError(t, err)
+
+	config := types.NotesConfig{Directory: "docs/release-notes"}
+	flavors := []types.Flavor{{Name: "upstream", Version: "1.2.0-uds.3"}}
+
+	warning, err := MissingUpgradeNotes(config, types.Flavor{Name: "upstream", Version: "2.0.0-uds.0"}, flavors)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0-uds.0-upstream is a major version bump from 1.2.0-uds.3-upstream but docs/release-notes has no 2.0.0-uds.0.md or 2.0.0-uds.0-upstream.md with upgrade notes", warning)
+
+	// Minor bumps, first releases and major bumps with notes are fine
+	warning, err = MissingUpgradeNotes(config, types.Flavor{Name: "upstream", Version: "1.3.0-uds.0"}, flavors)
+	require.NoError(t, err)
+	require.Empty(t, warning)
+
+	warning, err = MissingUpgradeNotes(config, types.Flavor{Name: "registry1", Version: "2.0.0-uds.0"}, flavors)
+	require.NoError(t, err)
+	require.Empty(t, warning)
+
+	require.NoError(t, os.MkdirAll("docs/release-notes", 0o755))
+	require.NoError(t, os.WriteFile("docs/release-notes/2.0.0-uds.0.md", []byte("Upgrade notes"), 0o644))
+	warning, err = MissingUpgradeNotes(config, types.Flavor{Name: "upstream", Version: "2.0.0-uds.0"}, flavors)
+	require.NoError(t, err)
+	require.Empty(t, warning)
+}
@@ -0,0 +1,258 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/diff"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Titles of the sections commits are grouped into, in the order they are written
+const (
+	Breaking     = "Breaking Changes"
+	Features     = "Features"
+	Fixes        = "Bug Fixes"
+	Dependencies = "Dependencies"
+	Other        = "Other Changes"
+)
+
+// ChangesHeading starts the list of changes in the notes
+const ChangesHeading = "## Changes"
+
+var sectionOrder = []string{Breaking, Features, Fixes, Dependencies, Other}
+
+// conventionalRegex matches conventional commit subjects such as feat(api)!: add endpoint
+var conventionalRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// releaseRegex matches the commits created by update-yaml and release pr, which are left out of the notes
+var releaseRegex = regexp.MustCompile(`^chore(\(release\))?: release `)
+
+// Commit is a commit included in a release
+type Commit struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	Body    string `json:"body,omitempty"`
+	Author  string `json:"author"`
+}
+
+// Range is the commits between the previous release of a flavor and HEAD
+type Range struct {
+	// Since is when the previous release was committed, zero for the first release
+	Since   time.Time
+	Commits map[string]bool
+}
+
+// Section is a group of changes with the same conventional commit type
+type Section struct {
+	Title   string   `json:"title"`
+	Entries []string `json:"entries"`
+}
+
+// CommitsSince returns the commits reachable from HEAD that are not reachable from the revision, newest first.
+// Every commit is returned when the revision is empty. Merge commits are skipped.
+func CommitsSince(revision string) ([]Commit, error) {
+	var commits []Commit
+	err := walkSince(revision, func(commit *object.Commit) {
+		if commit.NumParents() > 1 {
+			return
+		}
+		subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		commits = append(commits, Commit{
+			SHA:     commit.Hash.String(),
+			Subject: subject,
+			Body:    strings.TrimSpace(body),
+			Author:  commit.Author.Name,
+		})
+	})
+	return commits, err
+}
+
+// RangeSince returns the commits reachable from HEAD that are not reachable from the revision, including merge
+// commits, along with the commit time of the revision. Every commit is included when the revision is empty.
+func RangeSince(revision string) (Range, error) {
+	changes := Range{Commits: map[string]bool{}}
+	if revision != "" {
+		from, err := utils.GetRevisionCommit(revision)
+		if err != nil {
+			return changes, err
+		}
+		changes.Since = from.Committer.When
+	}
+
+	err := walkSince(revision, func(commit *object.Commit) {
+		changes.Commits[commit.Hash.String()] = true
+	})
+	return changes, err
+}
+
+// walkSince calls fn for each commit reachable from HEAD that is not reachable from the revision, newest first
+func walkSince(revision string, fn func(commit *object.Commit)) error {
+	repo, err := utils.OpenRepo()
+	if err != nil {
+		return err
+	}
+
+	released := map[plumbing.Hash]bool{}
+	if revision != "" {
+		from, err := utils.GetRevisionCommit(revision)
+		if err != nil {
+			return err
+		}
+		fromLog, err := repo.Log(&git.LogOptions{From: from.Hash})
+		if err != nil {
+			return err
+		}
+		err = fromLog.ForEach(func(commit *object.Commit) error {
+			released[commit.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	headLog, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return err
+	}
+
+	return headLog.ForEach(func(commit *object.Commit) error {
+		if !released[commit.Hash] {
+			fn(commit)
+		}
+		return nil
+	})
+}
+
+// Group sorts the commits into sections by their conventional commit type, leaving out release commits.
+// Sections without commits are omitted.
+func Group(commits []Commit) []Section {
+	entries := map[string][]string{}
+	for _, commit := range commits {
+		if releaseRegex.MatchString(commit.Subject) {
+			continue
+		}
+		title, entry := classify(commit)
+		entries[title] = append(entries[title], fmt.Sprintf("%s (%s)", entry, utils.ShortSHA(commit.SHA)))
+	}
+
+	var sections []Section
+	for _, title := range sectionOrder {
+		if len(entries[title]) > 0 {
+			sections = append(sections, Section{Title: title, Entries: entries[title]})
+		}
+	}
+	return sections
+}
+
+func classify(commit Commit) (string, string) {
+	matches := conventionalRegex.FindStringSubmatch(commit.Subject)
+	if matches == nil {
+		return Other, commit.Subject
+	}
+
+	commitType, scope, breaking, description := strings.ToLower(matches[1]), matches[2], matches[3] != "", matches[4]
+	entry := description
+	if scope != "" {
+		entry = fmt.Sprintf("**%s:** %s", scope, description)
+	}
+
+	switch {
+	case breaking || strings.Contains(commit.Body, "BREAKING CHANGE"):
+		return Breaking, entry
+	case commitType == "feat":
+		return Features, entry
+	case commitType == "fix":
+		return Fixes, entry
+	case commitType == "deps" || scope == "deps":
+		return Dependencies, description
+	default:
+		return Other, entry
+	}
+}
+
+// WriteMarkdown renders the sections as markdown lists under headings of the given level
+func WriteMarkdown(w io.Writer, sections []Section, level int) error {
+	for i, section := range sections {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n\n", strings.Repeat("#", level), section.Title); err != nil {
+			return err
+		}
+		for _, entry := range section.Entries {
+			if _, err := fmt.Fprintf(w, "- %s\n", entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Generate returns the notes for the next release of the flavor: the commits since the previous release of the
+// flavor grouped by type, followed by the upstream changes
+func Generate(flavor types.Flavor, flavors []types.Flavor) (string, error) {
+	since, err := PreviousRelease(flavor, flavors)
+	if err != nil {
+		return "", err
+	}
+
+	commits, err := CommitsSince(since)
+	if err != nil {
+		return "", err
+	}
+
+	notes, err := Changes(Group(commits))
+	if err != nil {
+		return "", err
+	}
+
+	upstream, err := diff.UpstreamChanges(flavor, flavors)
+	if err != nil {
+		return "", err
+	}
+	if upstream != "" {
+		notes += "\n" + upstream
+	}
+	return notes, nil
+}
+
+// PreviousRelease returns the tag of the previous release of the flavor, or an empty string for its first release
+func PreviousRelease(flavor types.Flavor, flavors []types.Flavor) (string, error) {
+	previousTag, err := utils.GetPreviousFlavorTag(flavor, flavors)
+	if err != nil || previousTag == nil {
+		return "", err
+	}
+	return previousTag.Name, nil
+}
+
+// Changes renders the sections under a ChangesHeading, or notes that there are no changes
+func Changes(sections []Section) (string, error) {
+	var notes strings.Builder
+	notes.WriteString(ChangesHeading + "\n\n")
+	if len(sections) == 0 {
+		notes.WriteString("No changes\n")
+		return notes.String(), nil
+	}
+	if err := WriteMarkdown(&notes, sections, 3); err != nil {
+		return "", err
+	}
+	return notes.String(), nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	commits := []Commit{
+		{SHA: "aaaaaaaa1", Subject: "feat(istio): add sidecar support"},
+		{SHA: "bbbbbbbb2", Subject: "fix: correct the service port"},
+		{SHA: "cccccccc3", Subject: "chore(deps): update podinfo to v6.7.0"},
+		{SHA: "dddddddd4", Subject: "feat!: drop the legacy values"},
+		{SHA: "eeeeeeee5", Subject: "refactor: simplify tasks", Body: "BREAKING CHANGE: tasks were renamed"},
+		{SHA: "ffffffff6", Subject: "Update README"},
+		{SHA: "000000007", Subject: "chore: release podinfo 1.0.0-uds.0-upstream"},
+		{SHA: "111111118", Subject: "docs: fix typo"},
+	}
+
+	sections := Group(commits)
+	require.Equal(t, []Section{
+		{Title: Breaking, Entries: []string{"drop the legacy values (dddddddd)", "simplify tasks (eeeeeeee)"}},
+		{Title: Features, Entries: []string{"**istio:** add sidecar support (aaaaaaaa)"}},
+		{Title: Fixes, Entries: []string{"correct the service port (bbbbbbbb)"}},
+		{Title: Dependencies, Entries: []string{"update podinfo to v6.7.0 (cccccccc)"}},
+		{Title: Other, Entries: []string{"Update README (ffffffff)", "fix typo (11111111)"}},
+	}, sections)
+
+	require.Empty(t, Group([]Commit{{SHA: "00000000", Subject: "chore(release): release 1.0.0-uds.0"}}))
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	sections := []Section{
+		{Title: Features, Entries: []string{"add sidecar support (aaaaaaaa)"}},
+		{Title: Fixes, Entries: []string{"correct the service port (bbbbbbbb)", "handle empty values (cccccccc)"}},
+	}
+
+	var out strings.Builder
+	require.NoError(t, WriteMarkdown(&out, sections, 3))
+	require.Equal(t, `### Features
+
+- add sidecar support (aaaaaaaa)
+
+### Bug Fixes
+
+- correct the service port (bbbbbbbb)
+- handle empty values (cccccccc)
+`, out.String())
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+)
+
+// Sources of the changes listed in the release notes
+const (
+	SourceCommits      = "commits"
+	SourcePullRequests = "pullRequests"
+)
+
+// Contributors is the title of the section listing the authors of the pull requests
+const Contributors = "Contributors"
+
+// DefaultCategories are used when the releaser.yaml does not configure any
+var DefaultCategories = []types.NotesCategory{
+	{Title: Breaking, Labels: []string{"breaking", "breaking-change"}},
+	{Title: Features, Labels: []string{"enhancement", "feature"}},
+	{Title: Fixes, Labels: []string{"bug", "fix"}},
+	{Title: Dependencies, Labels: []string{"dependencies"}},
+}
+
+// DefaultExcludeLabels are used when the releaser.yaml does not configure any
+var DefaultExcludeLabels = []string{"skip-changelog"}
+
+// PullRequest is a pull request on GitHub or a merge request on GitLab included in a release
+type PullRequest struct {
+	// Number is the number (GitHub) or IID (GitLab) of the pull request
+	Number int `json:"number"`
+	// Ref is how the platform refers to the pull request, such as #12 on GitHub or !12 on GitLab
+	Ref    string   `json:"ref"`
+	Title  string   `json:"title"`
+	URL    string   `json:"url,omitempty"`
+	Author string   `json:"author,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// GroupPullRequests sorts the pull requests into the sections of the first category with one of their labels,
+// leaving out pull requests with an excluded label. Pull requests without a category are listed under Other Changes
+// and the authors are listed last under Contributors. Sections without pull requests are omitted.
+func GroupPullRequests(pullRequests []PullRequest, config types.NotesConfig) []Section {
+	categories := config.Categories
+	if len(categories) == 0 {
+		categories = DefaultCategories
+	}
+	excludeLabels := config.ExcludeLabels
+	if len(excludeLabels) == 0 {
+		excludeLabels = DefaultExcludeLabels
+	}
+
+	entries := map[string][]string{}
+	var authors []string
+	seen := map[string]bool{}
+	for _, pr := range pullRequests {
+		if hasLabel(pr, excludeLabels) {
+			continue
+		}
+
+		title := Other
+		for _, category := range categories {
+			if hasLabel(pr, category.Labels) {
+				title = category.Title
+				break
+			}
+		}
+		entries[title] = append(entries[title], pullRequestEntry(pr))
+
+		if pr.Author != "" && !seen[pr.Author] {
+			seen[pr.Author] = true
+			authors = append(authors, "@"+pr.Author)
+		}
+	}
+
+	var sections []Section
+	for _, category := range categories {
+		if len(entries[category.Title]) > 0 {
+			sections = append(sections, Section{Title: category.Title, Entries: entries[category.Title]})
+			delete(entries, category.Title)
+		}
+	}
+	if len(entries[Other]) > 0 {
+		sections = append(sections, Section{Title: Other, Entries: entries[Other]})
+	}
+	if len(authors) > 0 {
+		sections = append(sections, Section{Title: Contributors, Entries: authors})
+	}
+	return sections
+}
+
+func pullRequestEntry(pr PullRequest) string {
+	ref := pr.Ref
+	if pr.URL != "" {
+		ref = fmt.Sprintf("[%s](%s)", pr.Ref, pr.URL)
+	}
+
+	entry := fmt.Sprintf("%s (%s)", pr.Title, ref)
+	if pr.Author != "" {
+		entry += " by @" + pr.Author
+	}
+	return entry
+}
+
+func hasLabel(pr PullRequest, labels []string) bool {
+	for _, label := range pr.Labels {
+		for _, match := range labels {
+			if strings.EqualFold(label, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
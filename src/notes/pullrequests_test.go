@@ -0,0 +1,39 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupPullRequests(t *testing.T) {
+	pullRequests := []PullRequest{
+		{Ref: "#14", Title: "Drop the legacy values", Author: "octocat", Labels: []string{"Breaking", "enhancement"}},
+		{Ref: "#13", Title: "Update podinfo", URL: "https://github.com/org/repo/pull/13", Author: "renovate", Labels: []string{"dependencies"}},
+		{Ref: "#12", Title: "Add sidecar support", Author: "octocat", Labels: []string{"enhancement"}},
+		{Ref: "#11", Title: "Tidy the tasks", Author: "hubot"},
+		{Ref: "#10", Title: "Fix a typo", Author: "hubot", Labels: []string{"bug", "skip-changelog"}},
+	}
+
+	require.Equal(t, []Section{
+		{Title: Breaking, Entries: []string{"Drop the legacy values (#14) by @octocat"}},
+		{Title: Features, Entries: []string{"Add sidecar support (#12) by @octocat"}},
+		{Title: Dependencies, Entries: []string{"Update podinfo ([#13](https://github.com/org/repo/pull/13)) by @renovate"}},
+		{Title: Other, Entries: []string{"Tidy the tasks (#11) by @hubot"}},
+		{Title: Contributors, Entries: []string{"@octocat", "@renovate", "@hubot"}},
+	}, GroupPullRequests(pullRequests, types.NotesConfig{}))
+
+	config := types.NotesConfig{
+		Categories:    []types.NotesCategory{{Title: "Chores", Labels: []string{"dependencies"}}},
+		ExcludeLabels: []string{"enhancement"},
+	}
+	require.Equal(t, []Section{
+		{Title: "Chores", Entries: []string{"Update podinfo ([#13](https://github.com/org/repo/pull/13)) by @renovate"}},
+		{Title: Other, Entries: []string{"Tidy the tasks (#11) by @hubot", "Fix a typo (#10) by @hubot"}},
+		{Title: Contributors, Entries: []string{"@renovate", "@hubot"}},
+	}, GroupPullRequests(pullRequests, config))
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// closingRegex matches a closing keyword followed by one or more issue references, such as "Fixes #1" or
+// "Closes group/project#45, #46 and #47"
+var closingRegex = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+((?:[\w.-]+(?:/[\w.-]+)+)?#\d+(?:(?:\s*,\s*|\s+and\s+)(?:[\w.-]+(?:/[\w.-]+)+)?#\d+)*)`)
+
+// referenceRegex matches a single issue reference in the list matched by closingRegex
+var referenceRegex = regexp.MustCompile(`((?:[\w.-]+(?:/[\w.-]+)+)?)#(\d+)`)
+
+// Reference is an issue referenced with a closing keyword in a commit message
+type Reference struct {
+	// Project is the owner/repo (GitHub) or group/project (GitLab) of the issue, empty for the current project
+	Project string `json:"project,omitempty"`
+	Number  int    `json:"number"`
+}
+
+// String returns the reference the way it is written in a commit message
+func (r Reference) String() string {
+	return r.Project + "#" + strconv.Itoa(r.Number)
+}
+
+// ParseReferences returns the issues the message references with a closing keyword, in the order they appear
+func ParseReferences(message string) []Reference {
+	var references []Reference
+	for _, match := range closingRegex.FindAllStringSubmatch(message, -1) {
+		for _, reference := range referenceRegex.FindAllStringSubmatch(match[1], -1) {
+			number, err := strconv.Atoi(reference[2])
+			if err != nil {
+				continue
+			}
+			references = append(references, Reference{Project: reference[1], Number: number})
+		}
+	}
+	return references
+}
+
+// ReferencesSince returns the issues referenced with a closing keyword by the commits reachable from HEAD that are
+// not reachable from the revision, including merge commits, without duplicates
+func ReferencesSince(revision string) ([]Reference, error) {
+	var references []Reference
+	seen := map[Reference]bool{}
+	err := walkSince(revision, func(commit *object.Commit) {
+		for _, reference := range ParseReferences(commit.Message) {
+			if !seen[reference] {
+				seen[reference] = true
+				references = append(references, reference)
+			}
+		}
+	})
+	return references, err
+}
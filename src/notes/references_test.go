@@ -0,0 +1,34 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected []Reference
+	}{
+		{message: "fix: correct the port\n\nFixes #123", expected: []Reference{{Number: 123}}},
+		{message: "Closes group/project#45", expected: []Reference{{Project: "group/project", Number: 45}}},
+		{message: "resolved: #1, #2 and other-org/repo#3", expected: []Reference{{Number: 1}, {Number: 2}, {Project: "other-org/repo", Number: 3}}},
+		{message: "Merge branch 'fix' into 'main'\n\nCloses #7 and closes group/sub/project#8\n\nSee merge request !5", expected: []Reference{{Number: 7}, {Project: "group/sub/project", Number: 8}}},
+		{message: "fix(values): correct the port (#12)"},
+		{message: "Relates to #9"},
+		{message: "prefixes #4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			require.Equal(t, test.expected, ParseReferences(test.message))
+		})
+	}
+
+	require.Equal(t, "group/project#45", Reference{Project: "group/project", Number: 45}.String())
+	require.Equal(t, "#45", Reference{Number: 45}.String())
+}
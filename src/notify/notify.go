@@ -0,0 +1,168 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// DefaultTemplate is the message text used when a notification does not set its own template
+const DefaultTemplate = `Released {{ .Package }} {{ .Tag }}{{ if .ReleaseURL }}: {{ .ReleaseURL }}{{ end }}{{ if .Summary }}
+
+{{ .Summary }}{{ end }}`
+
+// maxSummaryLines limits how much of the release notes are included in a message
+const maxSummaryLines = 15
+
+// Message is the data available to notification templates
+type Message struct {
+	Package    string `json:"package"`
+	Flavor     string `json:"flavor"`
+	Version    string `json:"version"`
+	Tag        string `json:"tag"`
+	ReleaseURL string `json:"releaseUrl"`
+	Summary    string `json:"summary"`
+}
+
+// NewMessage creates the message for a release of the flavor, summarizing the release notes
+func NewMessage(packageName string, flavor types.Flavor, releaseURL string, notes string) Message {
+	return Message{
+		Package:    packageName,
+		Flavor:     flavor.Name,
+		Version:    flavor.Version,
+		Tag:        fmt.Sprintf("%s-%s", flavor.Version, flavor.Name),
+		ReleaseURL: releaseURL,
+		Summary:    summarize(notes),
+	}
+}
+
+// Send posts the message to every configured notification. A failed notification does not stop the others
+// from being sent, all failures are returned together.
+func Send(ctx context.Context, notifications []types.NotificationConfig, msg Message, httpClient *http.Client) error {
+	var errs []error
+	for _, notification := range notifications {
+		if err := send(ctx, notification, msg, httpClient); err != nil {
+			errs = append(errs, fmt.Errorf("%s notification: %w", notification.Type, err))
+			continue
+		}
+		message.Infof("Sent %s notification for %s\n", notification.Type, msg.Tag)
+	}
+	return errors.Join(errs...)
+}
+
+func send(ctx context.Context, notification types.NotificationConfig, msg Message, httpClient *http.Client) error {
+	url := notification.URL
+	if notification.URLVarName != "" {
+		url = os.Getenv(notification.URLVarName)
+	}
+	if url == "" {
+		return errors.New("webhook url is not set")
+	}
+
+	text, err := render(notification.Template, msg)
+	if err != nil {
+		return err
+	}
+
+	payload, err := Payload(notification.Type, text, msg)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// Payload returns the JSON body expected by the incoming webhook of the notification type
+func Payload(notificationType string, text string, msg Message) (any, error) {
+	switch notificationType {
+	case "slack", "mattermost":
+		return map[string]string{"text": text}, nil
+	case "teams":
+		title := fmt.Sprintf("Released %s %s", msg.Package, msg.Tag)
+		card := map[string]any{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  title,
+			"title":    title,
+			// Teams renders the card text as markdown, which needs blank lines to keep line breaks
+			"text": strings.ReplaceAll(text, "\n", "\n\n"),
+		}
+		if msg.ReleaseURL != "" {
+			card["potentialAction"] = []map[string]any{{
+				"@type":   "OpenUri",
+				"name":    "View release",
+				"targets": []map[string]string{{"os": "default", "uri": msg.ReleaseURL}},
+			}}
+		}
+		return card, nil
+	case "webhook":
+		return struct {
+			Message
+			Text string `json:"text"`
+		}{Message: msg, Text: text}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notification type %q, must be slack, mattermost, teams or webhook", notificationType)
+	}
+}
+
+func render(text string, msg Message) (string, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+
+	tmpl, err := template.New("notification").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, msg); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return rendered.String(), nil
+}
+
+func summarize(notes string) string {
+	lines := strings.Split(strings.TrimSpace(notes), "\n")
+	if len(lines) <= maxSummaryLines {
+		return strings.TrimSpace(notes)
+	}
+	return strings.Join(lines[:maxSummaryLines], "\n") + "\n..."
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	received := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, "invalid_payload")
+			return
+		}
+
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received[r.URL.Path] = payload
+	}))
+	defer server.Close()
+
+	t.Setenv("TEAMS_WEBHOOK_URL", server.URL+"/teams")
+
+	notifications := []types.NotificationConfig{
+		{Type: "slack", URL: server.URL + "/slack"},
+		{Type: "mattermost", URL: server.URL + "/broken"},
+		{Type: "teams", URLVarName: "TEAMS_WEBHOOK_URL"},
+		{Type: "webhook", URL: server.URL + "/webhook", Template: "{{ .Package }} {{ .Version }} ({{ .Flavor }})"},
+		{Type: "email", URL: server.URL + "/email"},
+		{Type: "slack", URLVarName: "UNSET_WEBHOOK_URL"},
+	}
+
+	notes := "## Upstream changes\n\n- `podinfo` `6.4.0` → `6.5.0`\n"
+	msg := NewMessage("podinfo", types.Flavor{Name: "upstream", Version: "6.5.0-uds.0"}, "https://github.com/defenseunicorns/uds-package-podinfo/releases/tag/6.5.0-uds.0-upstream", notes)
+
+	err := Send(context.Background(), notifications, msg, server.Client())
+	require.Error(t, err)
+	require.ErrorContains(t, err, "mattermost notification: unexpected status 400 Bad Request: invalid_payload")
+	require.ErrorContains(t, err, `email notification: unsupported notification type "email"`)
+	require.ErrorContains(t, err, "slack notification: webhook url is not set")
+
+	// Failed notifications do not stop the others from being sent
+	require.Len(t, received, 3)
+
+	require.Equal(t, "Released podinfo 6.5.0-uds.0-upstream: https://github.com/defenseunicorns/uds-package-podinfo/releases/tag/6.5.0-uds.0-upstream\n\n"+strings.TrimSpace(notes), received["/slack"]["text"])
+
+	require.Equal(t, "MessageCard", received["/teams"]["@type"])
+	require.Equal(t, "Released podinfo 6.5.0-uds.0-upstream", received["/teams"]["title"])
+	require.Contains(t, received["/teams"]["potentialAction"], map[string]any{
+		"@type":   "OpenUri",
+		"name":    "View release",
+		"targets": []any{map[string]any{"os": "default", "uri": msg.ReleaseURL}},
+	})
+
+	require.Equal(t, "podinfo 6.5.0-uds.0 (upstream)", received["/webhook"]["text"])
+	require.Equal(t, "6.5.0-uds.0-upstream", received["/webhook"]["tag"])
+	require.Equal(t, msg.ReleaseURL, received["/webhook"]["releaseUrl"])
+}
+
+func TestNewMessageSummary(t *testing.T) {
+	notes := strings.Repeat("- change\n", 20)
+
+	msg := NewMessage("podinfo", types.Flavor{Name: "upstream", Version: "6.5.0-uds.0"}, "", notes)
+	require.Len(t, strings.Split(msg.Summary, "\n"), maxSummaryLines+1)
+	require.True(t, strings.HasSuffix(msg.Summary, "\n..."))
+
+	text, err := render("", msg)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(text, "Released podinfo 6.5.0-uds.0-upstream\n\n- change\n"))
+
+	_, err = render("{{ .Missing }}", msg)
+	require.ErrorContains(t, err, "invalid template")
+}
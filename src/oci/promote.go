@@ -0,0 +1,110 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// PromoteOptions are the registries a flavor's package and bundles are copied between
+type PromoteOptions struct {
+	// From and To replace the flavor's publishPackageUrl, From defaults to the flavor's publishPackageUrl
+	From string
+	To   string
+	// BundleFrom and BundleTo replace the flavor's publishBundleUrl. BundleFrom defaults to From when it is set and
+	// otherwise to the flavor's publishBundleUrl, or its publishPackageUrl when that is not set either. BundleTo
+	// defaults to To.
+	BundleFrom string
+	BundleTo   string
+}
+
+// Promote copies the manifests and blobs of the flavor's package, and bundles when PublishBundle is set, tagged
+// <version>-<flavor> from one registry to another without rebuilding them. The copies are verified to have the same
+// digests as the originals, which are returned as they were published to the destination.
+func Promote(ctx context.Context, flavor types.Flavor, promoteOpts PromoteOptions, opts RegistryOptions) ([]Artifact, error) {
+	source, destination := flavor, flavor
+	if promoteOpts.From != "" {
+		// Registries given on the command line replace the configured ones for the bundle as well
+		source.PublishPackageUrl, source.PublishBundleUrl = promoteOpts.From, promoteOpts.From
+	}
+	if promoteOpts.BundleFrom != "" {
+		source.PublishBundleUrl = promoteOpts.BundleFrom
+	}
+	if source.PublishBundleUrl == "" {
+		source.PublishBundleUrl = source.PublishPackageUrl
+	}
+	if source.PublishPackageUrl == "" || promoteOpts.To == "" {
+		return nil, errors.New("the registries to promote from and to are required")
+	}
+
+	destination.PublishPackageUrl, destination.PublishBundleUrl = promoteOpts.To, promoteOpts.BundleTo
+	if destination.PublishBundleUrl == "" {
+		destination.PublishBundleUrl = promoteOpts.To
+	}
+
+	sourceReferences, err := References(source)
+	if err != nil {
+		return nil, err
+	}
+	destinationReferences, err := References(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for i, sourceReference := range sourceReferences {
+		artifact, err := copyArtifact(ctx, sourceReference, destinationReferences[i], opts)
+		if err != nil {
+			return artifacts, err
+		}
+
+		message.Infof("Promoted %s to %s@%s\n", sourceReference, artifact.Reference, artifact.Digest)
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, nil
+}
+
+// copyArtifact copies the manifest the source reference points to, along with the manifests and blobs it references,
+// to the destination and checks the destination resolves to the same digest
+func copyArtifact(ctx context.Context, sourceReference string, destinationReference string, opts RegistryOptions) (Artifact, error) {
+	sourceRepo, err := NewRepository(sourceReference, opts)
+	if err != nil {
+		return Artifact{}, err
+	}
+	destinationRepo, err := NewRepository(destinationReference, opts)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	sourceDesc, err := sourceRepo.Resolve(ctx, sourceRepo.Reference.Reference)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return Artifact{}, fmt.Errorf("%s: %w", sourceReference, ErrNotPublished)
+		}
+		return Artifact{}, err
+	}
+
+	copied, err := oras.Copy(ctx, sourceRepo, sourceDesc.Digest.String(), destinationRepo, destinationRepo.Reference.Reference, oras.DefaultCopyOptions)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("unable to copy %s to %s: %w", sourceReference, destinationReference, err)
+	}
+
+	digest, err := Resolve(ctx, destinationReference, opts)
+	if err != nil {
+		return Artifact{}, err
+	}
+	if copied.Digest != sourceDesc.Digest || digest != sourceDesc.Digest.String() {
+		return Artifact{}, fmt.Errorf("%s resolves to %s after promoting, expected %s", destinationReference, digest, sourceDesc.Digest)
+	}
+
+	return Artifact{Reference: destinationReference, Digest: digest}, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromote(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	packagePath := createTestPackage(t)
+
+	staging := httptest.NewServer(registry.New())
+	defer staging.Close()
+	stagingHost := strings.TrimPrefix(staging.URL, "http://")
+	production := httptest.NewServer(registry.New())
+	defer production.Close()
+	productionHost := strings.TrimPrefix(production.URL, "http://")
+
+	testutil.Chdir(t)
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+
+	flavor := types.Flavor{
+		Name:              "upstream",
+		Version:           "1.0.0-uds.0",
+		PublishPackageUrl: "oci://" + stagingHost + "/packages",
+	}
+	opts := RegistryOptions{PlainHTTP: true}
+	require.NoError(t, PublishPackage(context.Background(), flavor, packagePath, opts))
+	published, err := VerifyPublished(context.Background(), flavor, opts)
+	require.NoError(t, err)
+
+	promoteOpts := PromoteOptions{From: flavor.PublishPackageUrl, To: productionHost + "/uds/packages"}
+	artifacts, err := Promote(context.Background(), flavor, promoteOpts, opts)
+	require.NoError(t, err)
+	require.Equal(t, []Artifact{{
+		Reference: productionHost + "/uds/packages/testing-package:1.0.0-uds.0-upstream",
+		Digest:    published[0].Digest,
+	}}, artifacts)
+
+	flavor.PublishPackageUrl = promoteOpts.To
+	promoted, err := VerifyPublished(context.Background(), flavor, opts)
+	require.NoError(t, err)
+	require.Equal(t, published[0].Digest, promoted[0].Digest)
+
+	// Promoting again is a no-op that still verifies the digests
+	_, err = Promote(context.Background(), flavor, promoteOpts, opts)
+	require.NoError(t, err)
+
+	// Versions that are not published to the source registry are not promoted
+	flavor.Version = "1.0.1-uds.0"
+	_, err = Promote(context.Background(), flavor, promoteOpts, opts)
+	require.ErrorIs(t, err, ErrNotPublished)
+
+	_, err = Promote(context.Background(), flavor, PromoteOptions{From: flavor.PublishPackageUrl}, opts)
+	require.ErrorContains(t, err, "the registries to promote from and to are required")
+
+	// Without --from the bundle is copied from the flavor's publishBundleUrl, any manifest will do as the bundle
+	flavor.Version = "1.0.0-uds.0"
+	flavor.PublishPackageUrl = promoteOpts.From
+	flavor.PublishBundle = true
+	flavor.PublishBundleUrl = "oci://" + stagingHost + "/bundles"
+	require.NoError(t, os.Mkdir("bundle", 0o755))
+	require.NoError(t, os.WriteFile("bundle/uds-bundle.yaml", []byte("kind: UDSBundle\nmetadata:\n  name: testing-bundle\n"), 0o644))
+	bundle, err := copyArtifact(context.Background(), published[0].Reference, stagingHost+"/bundles/testing-bundle:1.0.0-uds.0-upstream", opts)
+	require.NoError(t, err)
+
+	artifacts, err = Promote(context.Background(), flavor, PromoteOptions{To: promoteOpts.To}, opts)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 2)
+	require.Equal(t, Artifact{Reference: productionHost + "/uds/packages/testing-bundle:1.0.0-uds.0-upstream", Digest: bundle.Digest}, artifacts[1])
+
+	// An explicit --from replaces the flavor's publishBundleUrl too
+	_, err = Promote(context.Background(), flavor, promoteOpts, opts)
+	require.ErrorIs(t, err, ErrNotPublished)
+	require.ErrorContains(t, err, stagingHost+"/packages/testing-bundle:1.0.0-uds.0-upstream")
+
+	_, err = copyArtifact(context.Background(), published[0].Reference, stagingHost+"/packages/testing-bundle:1.0.0-uds.0-upstream", opts)
+	require.NoError(t, err)
+	artifacts, err = Promote(context.Background(), flavor, promoteOpts, opts)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 2)
+
+	promoteOpts.BundleTo = productionHost + "/uds/bundles"
+	artifacts, err = Promote(context.Background(), flavor, promoteOpts, opts)
+	require.NoError(t, err)
+	require.Equal(t, productionHost+"/uds/bundles/testing-bundle:1.0.0-uds.0-upstream", artifacts[1].Reference)
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Tags returns every tag in the repository of the reference
+func Tags(ctx context.Context, reference string, opts RegistryOptions) ([]string, error) {
+	repo, err := NewRepository(reference, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	err = repo.Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the tags of %s: %w", repo.Reference.Repository, err)
+	}
+	return tags, nil
+}
+
+// DeleteTags deletes the manifests the tags point to from the repository of the reference, returning the references
+// that were deleted. A manifest another tag still points to, such as a release candidate tagged with its final
+// version, is kept. When dryRun is set nothing is deleted and the references that would be are returned.
+func DeleteTags(ctx context.Context, reference string, tags []string, dryRun bool, opts RegistryOptions) ([]string, error) {
+	repo, err := NewRepository(reference, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := Tags(ctx, reference, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := map[string]string{}
+	for _, tag := range existing {
+		if slices.Contains(tags, tag) {
+			continue
+		}
+		desc, err := repo.Resolve(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+		kept[desc.Digest.String()] = tag
+	}
+
+	// Every tag is resolved before deleting anything as deleting a manifest removes all of the tags pointing to it
+	descs := make([]ocispec.Descriptor, len(tags))
+	for i, tag := range tags {
+		descs[i], err = repo.Resolve(ctx, tag)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %s: %w", tagReference(repo, tag), err)
+		}
+	}
+
+	var deleted []string
+	deletedDigests := map[string]bool{}
+	for i, tag := range tags {
+		digest := descs[i].Digest.String()
+		if other, ok := kept[digest]; ok {
+			message.Warnf("Keeping %s as %s points to the same manifest\n", tagReference(repo, tag), other)
+			continue
+		}
+
+		if dryRun {
+			deleted = append(deleted, tagReference(repo, tag))
+			continue
+		}
+		if !deletedDigests[digest] {
+			if err := repo.Delete(ctx, descs[i]); err != nil {
+				return deleted, fmt.Errorf("unable to delete %s: %w", tagReference(repo, tag), err)
+			}
+			deletedDigests[digest] = true
+		}
+		message.Infof("Deleted %s@%s\n", tagReference(repo, tag), digest)
+		deleted = append(deleted, tagReference(repo, tag))
+	}
+	return deleted, nil
+}
+
+func tagReference(repo *remote.Repository, tag string) string {
+	return fmt.Sprintf("%s/%s:%s", repo.Reference.Registry, repo.Reference.Repository, tag)
+}
+
+// PruneSnapshots deletes the <version>-<flavor>-dev.<date>.<shortsha> snapshots of the flavor's package, and bundles when
+// PublishBundle is set, that are dated before the cutoff, returning the references that were deleted. The snapshot
+// of the flavor itself is always kept, even when its commit is older than the cutoff.
+func PruneSnapshots(ctx context.Context, flavor types.Flavor, cutoff time.Time, opts RegistryOptions) ([]string, error) {
+	references, err := References(flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, reference := range references {
+		tags, err := Tags(ctx, reference, opts)
+		if err != nil {
+			return deleted, err
+		}
+
+		var expired []string
+		for _, tag := range tags {
+			if tag == FlavorTag(flavor) {
+				continue
+			}
+			if _, date, ok := utils.ParseSnapshot(tag, flavor.Name); ok && date.Before(cutoff) {
+				expired = append(expired, tag)
+			}
+		}
+		if len(expired) == 0 {
+			continue
+		}
+
+		pruned, err := DeleteTags(ctx, reference, expired, false, opts)
+		deleted = append(deleted, pruned...)
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestPruneSnapshots(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+	opts := RegistryOptions{PlainHTTP: true}
+
+	testutil.Chdir(t)
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+
+	repoReference := registryHost + "/packages/testing-package"
+	repo, err := NewRepository(repoReference, opts)
+	require.NoError(t, err)
+	// Each tag gets its own manifest except the release candidate, which shares the manifest of its final version
+	digests := map[string]string{}
+	for _, tag := range []string{
+		"1.0.0-uds.0-upstream-dev.20261001.aaaaaaaa",
+		"1.0.0-uds.0-upstream-dev.20261010.bbbbbbbb",
+		"1.0.0-uds.0-upstream-dev.20261018.cccccccc",
+		"1.0.0-uds.0-registry1-dev.20261001.dddddddd",
+		"1.0.0-uds.0-upstream",
+		"1.0.0-uds.0-rc.1-upstream",
+	} {
+		packOpts := oras.PackManifestOptions{ManifestAnnotations: map[string]string{"tag": strings.TrimSuffix(tag, "-rc.1-upstream")}}
+		if tag == "1.0.0-uds.0-upstream" {
+			packOpts.ManifestAnnotations["tag"] = "1.0.0-uds.0"
+		}
+		desc, err := oras.PackManifest(context.Background(), repo, oras.PackManifestVersion1_1, "application/vnd.uds.test", packOpts)
+		require.NoError(t, err)
+		require.NoError(t, repo.Tag(context.Background(), desc, tag))
+		digests[tag] = desc.Digest.String()
+	}
+
+	flavor := types.Flavor{
+		Name:              "upstream",
+		Version:           "1.0.0-uds.0-upstream-dev.20261001.aaaaaaaa",
+		PublishPackageUrl: "oci://" + registryHost + "/packages",
+	}
+
+	// The snapshot being released is kept even though its commit is older than the cutoff
+	deleted, err := PruneSnapshots(context.Background(), flavor, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{repoReference + ":1.0.0-uds.0-upstream-dev.20261010.bbbbbbbb"}, deleted)
+
+	// The test registry keeps listing the tags of deleted manifests, which registries implementing the distribution
+	// spec do not, so the manifests are looked up by digest instead
+	tags, err := Tags(context.Background(), repoReference, opts)
+	require.NoError(t, err)
+	require.Len(t, tags, 6)
+	for tag, digest := range digests {
+		_, err = repo.Resolve(context.Background(), digest)
+		if tag == "1.0.0-uds.0-upstream-dev.20261010.bbbbbbbb" {
+			require.Error(t, err, tag)
+		} else {
+			require.NoError(t, err, tag)
+		}
+	}
+
+	// Manifests that a remaining tag points to are not deleted
+	deleted, err = DeleteTags(context.Background(), repoReference, []string{"1.0.0-uds.0-rc.1-upstream"}, false, opts)
+	require.NoError(t, err)
+	require.Empty(t, deleted)
+
+	// A dry run only returns what would be deleted
+	deleted, err = DeleteTags(context.Background(), repoReference, []string{"1.0.0-uds.0-upstream-dev.20261018.cccccccc"}, true, opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{repoReference + ":1.0.0-uds.0-upstream-dev.20261018.cccccccc"}, deleted)
+	_, err = repo.Resolve(context.Background(), digests["1.0.0-uds.0-upstream-dev.20261018.cccccccc"])
+	require.NoError(t, err)
+	_, err = Resolve(context.Background(), repoReference+":1.0.0-uds.0-rc.1-upstream", opts)
+	require.NoError(t, err)
+}
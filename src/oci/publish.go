@@ -0,0 +1,226 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	udsConfig "github.com/defenseunicorns/uds-cli/src/config"
+	udsBundle "github.com/defenseunicorns/uds-cli/src/pkg/bundle"
+	udsUtils "github.com/defenseunicorns/uds-cli/src/pkg/utils"
+	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/mholt/archiver/v3"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	zarfTypes "github.com/zarf-dev/zarf/src/types"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// publishConcurrency is the number of layers pushed to the registry at once
+const publishConcurrency = 3
+
+// PublishOptions holds the locations of the built artifacts
+type PublishOptions struct {
+	PackageDir string
+	BundleDir  string
+	Arch       string
+}
+
+// Publish pushes the Zarf package for the flavor, and its bundle when PublishBundle is set, to the configured OCI urls
+func Publish(ctx context.Context, flavor types.Flavor, opts PublishOptions, registryOpts RegistryOptions) error {
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return err
+	}
+
+	err = PublishPackage(ctx, flavor, filepath.Join(opts.PackageDir, PackageFileName(packageName, opts.Arch, flavor.Version)), registryOpts)
+	if err != nil {
+		return err
+	}
+
+	if !flavor.PublishBundle {
+		return nil
+	}
+
+	for _, bundlePath := range utils.GetBundleFiles(flavor, opts.BundleDir) {
+		bundleName, err := utils.GetBundleName(bundlePath)
+		if err != nil {
+			return err
+		}
+
+		err = PublishBundle(flavor, filepath.Join(filepath.Dir(bundlePath), BundleFileName(bundleName, opts.Arch, flavor.Version)), opts.Arch, registryOpts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishPackage pushes the Zarf package tarball at packagePath to the flavor's PublishPackageUrl tagged as <version>-<flavor>
+func PublishPackage(ctx context.Context, flavor types.Flavor, packagePath string, opts RegistryOptions) error {
+	if flavor.PublishPackageUrl == "" {
+		return fmt.Errorf("publishPackageUrl is not set for flavor %s", flavor.Name)
+	}
+
+	if _, err := os.Stat(packagePath); err != nil {
+		return fmt.Errorf("unable to find package for flavor %s: %w", flavor.Name, err)
+	}
+
+	source, err := sources.New(&zarfTypes.ZarfPackageOptions{PackageSource: packagePath})
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "uds-pk-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	paths := layout.New(tmpDir)
+	pkg, _, err := source.LoadPackage(ctx, paths, filters.Empty(), false)
+	if err != nil {
+		return fmt.Errorf("unable to load the package: %w", err)
+	}
+
+	if pkg.Metadata.Version != flavor.Version {
+		return fmt.Errorf("package version %s does not match flavor version %s", pkg.Metadata.Version, flavor.Version)
+	}
+	if pkg.Build.Flavor != "" && pkg.Build.Flavor != flavor.Name {
+		return fmt.Errorf("package was built for flavor %s not %s", pkg.Build.Flavor, flavor.Name)
+	}
+
+	ref := Reference(flavor.PublishPackageUrl, pkg.Metadata.Name, FlavorTag(flavor))
+	mods, err := remoteModifiers(ref, opts)
+	if err != nil {
+		return err
+	}
+	remote, err := zoci.NewRemote(ref, oci.PlatformForArch(pkg.Build.Architecture), mods...)
+	if err != nil {
+		return err
+	}
+
+	message.Infof("Publishing package %s\n", ref)
+
+	return remote.PublishPackage(ctx, &pkg, paths, publishConcurrency)
+}
+
+// PublishBundle pushes the UDS bundle tarball at bundlePath built for arch to the flavor's PublishBundleUrl tagged as
+// <version>-<flavor>
+func PublishBundle(flavor types.Flavor, bundlePath string, arch string, opts RegistryOptions) error {
+	if flavor.PublishBundleUrl == "" {
+		return fmt.Errorf("publishBundleUrl is not set for flavor %s", flavor.Name)
+	}
+
+	if _, err := os.Stat(bundlePath); err != nil {
+		return fmt.Errorf("unable to find bundle for flavor %s: %w", flavor.Name, err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "uds-pk-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The same steps as uds publish, with a remote that uses our registry options instead of the global ones
+	provider, err := udsBundle.NewBundleProvider(bundlePath, tmpDir)
+	if err != nil {
+		return err
+	}
+	metadataPaths, err := provider.LoadBundleMetadata()
+	if err != nil {
+		return err
+	}
+	var bundle uds.UDSBundle
+	if err := udsUtils.ReadYAMLStrict(metadataPaths[udsConfig.BundleYAML], &bundle); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(tmpDir, "blobs")); err != nil {
+		return err
+	}
+	if err := archiver.Unarchive(bundlePath, tmpDir); err != nil {
+		return err
+	}
+
+	ref := Reference(flavor.PublishBundleUrl, bundle.Metadata.Name, FlavorTag(flavor))
+	mods, err := remoteModifiers(ref, opts)
+	if err != nil {
+		return err
+	}
+	remote, err := zoci.NewRemote(ref, oci.PlatformForArch(arch), mods...)
+	if err != nil {
+		return err
+	}
+
+	message.Infof("Publishing bundle %s\n", ref)
+
+	return provider.PublishBundle(bundle, remote.OrasRemote)
+}
+
+// remoteModifiers connects a Zarf remote for the reference using the plain HTTP setting and the TLS and proxy settings
+// the HTTP client has for the registry, rather than Zarf's global options
+func remoteModifiers(reference string, opts RegistryOptions) ([]oci.Modifier, error) {
+	mods := []oci.Modifier{oci.WithPlainHTTP(opts.PlainHTTP)}
+	if opts.HTTPClient == nil {
+		return mods, nil
+	}
+
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	configured, ok := utils.HostTransport(opts.HTTPClient, ref.Registry)
+	if !ok {
+		return nil, fmt.Errorf("unable to apply the HTTP settings to the client for %s", ref.Registry)
+	}
+
+	// The remote pushes through a progress transport that wraps the transport of its client, so the settings are set
+	// on that transport rather than replacing it
+	mods = append(mods, func(remote *oci.OrasRemote) {
+		client, ok := remote.Repo().Client.(*auth.Client)
+		if !ok {
+			return
+		}
+		if transport, ok := client.Client.Transport.(*http.Transport); ok {
+			transport.TLSClientConfig = configured.TLSClientConfig.Clone()
+			transport.Proxy = configured.Proxy
+		}
+	})
+	return mods, nil
+}
+
+// FlavorTag returns the OCI tag used for a flavor's published artifacts, a snapshot already carries the flavor
+func FlavorTag(flavor types.Flavor) string {
+	if _, _, ok := utils.ParseSnapshot(flavor.Version, flavor.Name); ok {
+		return flavor.Version
+	}
+	return fmt.Sprintf("%s-%s", flavor.Version, flavor.Name)
+}
+
+// Reference returns the full OCI reference for an artifact published under the given url
+func Reference(url, name, tag string) string {
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(strings.TrimPrefix(url, "oci://"), "/"), name, tag)
+}
+
+// PackageFileName returns the file name Zarf uses for a built package
+func PackageFileName(name, arch, version string) string {
+	return fmt.Sprintf("zarf-package-%s-%s-%s.tar.zst", name, arch, version)
+}
+
+// BundleFileName returns the file name UDS uses for a built bundle
+func BundleFileName(name, arch, version string) string {
+	return fmt.Sprintf("uds-bundle-%s-%s-%s.tar.zst", name, arch, version)
+}
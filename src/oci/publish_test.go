@@ -0,0 +1,132 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/mholt/archiver/v3"
+	"github.com/stretchr/testify/require"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// createTestPackage writes a minimal Zarf package tarball built for the upstream flavor and returns its path
+func createTestPackage(t *testing.T) string {
+	t.Helper()
+
+	srcDir := t.TempDir()
+	outDir := t.TempDir()
+
+	// A package without components only needs an empty checksums.txt and a matching aggregate checksum
+	checksumsPath := filepath.Join(srcDir, "checksums.txt")
+	require.NoError(t, os.WriteFile(checksumsPath, []byte(""), 0o644))
+	checksum := sha256.Sum256([]byte(""))
+
+	var zarfPackage zarf.ZarfPackage
+	zarfPackage.Kind = zarf.ZarfPackageConfig
+	zarfPackage.Metadata.Name = "testing-package"
+	zarfPackage.Metadata.Version = "1.0.0-uds.0"
+	zarfPackage.Metadata.AggregateChecksum = hex.EncodeToString(checksum[:])
+	zarfPackage.Build.Architecture = "amd64"
+	zarfPackage.Build.Flavor = "upstream"
+
+	data, err := goyaml.Marshal(zarfPackage)
+	require.NoError(t, err)
+	zarfYamlPath := filepath.Join(srcDir, "zarf.yaml")
+	require.NoError(t, os.WriteFile(zarfYamlPath, data, 0o644))
+
+	packagePath := filepath.Join(outDir, PackageFileName("testing-package", "amd64", "1.0.0-uds.0"))
+	require.NoError(t, archiver.Archive([]string{zarfYamlPath, checksumsPath}, packagePath))
+
+	return packagePath
+}
+
+func TestPublishPackage(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	packagePath := createTestPackage(t)
+
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+
+	flavor := types.Flavor{
+		Name:              "upstream",
+		Version:           "1.0.0-uds.0",
+		PublishPackageUrl: "oci://" + registryHost + "/packages",
+	}
+	opts := RegistryOptions{PlainHTTP: true}
+
+	err := PublishPackage(context.Background(), flavor, packagePath, opts)
+	require.NoError(t, err)
+
+	repo, err := remote.NewRepository(registryHost + "/packages/testing-package")
+	require.NoError(t, err)
+	repo.PlainHTTP = true
+
+	_, err = repo.Resolve(context.Background(), "1.0.0-uds.0-upstream")
+	require.NoError(t, err)
+
+	// A flavor with a different version than the built package should not be published
+	flavor.Version = "1.0.1-uds.0"
+	err = PublishPackage(context.Background(), flavor, packagePath, opts)
+	require.ErrorContains(t, err, "does not match flavor version")
+
+	// A package built for a different flavor should not be published
+	flavor.Version = "1.0.0-uds.0"
+	flavor.Name = "registry1"
+	err = PublishPackage(context.Background(), flavor, packagePath, opts)
+	require.ErrorContains(t, err, "package was built for flavor upstream")
+}
+
+func TestPublishPackageHTTPClient(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	packagePath := createTestPackage(t)
+
+	server := httptest.NewTLSServer(registry.New())
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "https://")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o644))
+
+	flavor := types.Flavor{
+		Name:              "upstream",
+		Version:           "1.0.0-uds.0",
+		PublishPackageUrl: "oci://" + registryHost + "/packages",
+	}
+
+	// The registry's certificate is only trusted through the CA file of the HTTP client
+	err := PublishPackage(context.Background(), flavor, packagePath, RegistryOptions{})
+	require.ErrorContains(t, err, "certificate")
+
+	httpClient, err := utils.NewHTTPClient(types.HTTPConfig{CAFile: caFile})
+	require.NoError(t, err)
+	require.NoError(t, PublishPackage(context.Background(), flavor, packagePath, RegistryOptions{HTTPClient: httpClient}))
+
+	_, err = Resolve(context.Background(), Reference(flavor.PublishPackageUrl, "testing-package", FlavorTag(flavor)), RegistryOptions{HTTPClient: httpClient})
+	require.NoError(t, err)
+}
+
+func TestReference(t *testing.T) {
+	require.Equal(t, "ghcr.io/defenseunicorns/packages/uds/podinfo:1.0.0-uds.0-upstream",
+		Reference("oci://ghcr.io/defenseunicorns/packages/uds/", "podinfo", "1.0.0-uds.0-upstream"))
+	require.Equal(t, "registry.example.com/podinfo:1.0.0-uds.0-registry1",
+		Reference("registry.example.com", "podinfo", "1.0.0-uds.0-registry1"))
+}
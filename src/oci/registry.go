@@ -0,0 +1,153 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+// RegistryOptions holds the settings used to connect to OCI registries
+type RegistryOptions struct {
+	HTTPClient *http.Client
+	PlainHTTP  bool
+}
+
+// Artifact is a published package or bundle and the digest of its manifest
+type Artifact struct {
+	Reference string
+	Digest    string
+}
+
+// ErrNotPublished is returned when the expected tag does not exist in the registry
+var ErrNotPublished = errors.New("artifact is not published")
+
+// VerifyPublished checks that the flavor's package, and bundle when PublishBundle is set, are published with the <version>-<flavor> tag
+func VerifyPublished(ctx context.Context, flavor types.Flavor, opts RegistryOptions) ([]Artifact, error) {
+	references, err := References(flavor)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for _, reference := range references {
+		digest, err := Resolve(ctx, reference, opts)
+		if err != nil {
+			return artifacts, err
+		}
+
+		message.Infof("Found %s@%s\n", reference, digest)
+		artifacts = append(artifacts, Artifact{Reference: reference, Digest: digest})
+	}
+
+	return artifacts, nil
+}
+
+// References returns the <version>-<flavor> references of the flavor's package, and bundles when PublishBundle is set
+func References(flavor types.Flavor) ([]string, error) {
+	if flavor.PublishPackageUrl == "" {
+		return nil, fmt.Errorf("publishPackageUrl is not set for flavor %s", flavor.Name)
+	}
+
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return nil, err
+	}
+
+	references := []string{Reference(flavor.PublishPackageUrl, packageName, FlavorTag(flavor))}
+
+	if flavor.PublishBundle {
+		if flavor.PublishBundleUrl == "" {
+			return nil, fmt.Errorf("publishBundleUrl is not set for flavor %s", flavor.Name)
+		}
+
+		for _, bundlePath := range utils.GetBundleFiles(flavor, "") {
+			bundleName, err := utils.GetBundleName(bundlePath)
+			if err != nil {
+				return nil, err
+			}
+			references = append(references, Reference(flavor.PublishBundleUrl, bundleName, FlavorTag(flavor)))
+		}
+	}
+
+	return references, nil
+}
+
+// Resolve returns the digest of the manifest the reference points to, or ErrNotPublished if the tag does not exist
+func Resolve(ctx context.Context, reference string, opts RegistryOptions) (string, error) {
+	repo, err := NewRepository(reference, opts)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return "", fmt.Errorf("%s: %w", reference, ErrNotPublished)
+		}
+		return "", err
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// NewRepository returns a client for the repository of the given reference using credentials from the Docker config
+func NewRepository(reference string, opts RegistryOptions) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(strings.TrimPrefix(reference, "oci://"))
+	if err != nil {
+		return nil, err
+	}
+
+	credStore, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = retry.DefaultClient
+	}
+
+	repo.PlainHTTP = opts.PlainHTTP
+	repo.Client = &auth.Client{
+		Client:     httpClient,
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(credStore),
+	}
+
+	return repo, nil
+}
+
+// Tag adds the tag to the manifest the reference points to without copying it, returning the digest of the manifest
+func Tag(ctx context.Context, reference string, tag string, opts RegistryOptions) (string, error) {
+	repo, err := NewRepository(reference, opts)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return "", fmt.Errorf("%s: %w", reference, ErrNotPublished)
+		}
+		return "", err
+	}
+
+	if err := repo.Tag(ctx, desc, tag); err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPublished(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	packagePath := createTestPackage(t)
+
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+
+	flavor := types.Flavor{
+		Name:              "upstream",
+		Version:           "1.0.0-uds.0",
+		PublishPackageUrl: "oci://" + registryHost + "/packages",
+	}
+	opts := RegistryOptions{PlainHTTP: true}
+
+	// VerifyPublished reads the package name from the zarf.yaml in the working directory
+	testutil.Chdir(t)
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+
+	_, err := VerifyPublished(context.Background(), flavor, opts)
+	require.ErrorIs(t, err, ErrNotPublished)
+
+	require.NoError(t, PublishPackage(context.Background(), flavor, packagePath, opts))
+
+	artifacts, err := VerifyPublished(context.Background(), flavor, opts)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	require.Equal(t, registryHost+"/packages/testing-package:1.0.0-uds.0-upstream", artifacts[0].Reference)
+	require.True(t, strings.HasPrefix(artifacts[0].Digest, "sha256:"))
+
+	// Tagging points the final version at the same manifest
+	digest, err := Tag(context.Background(), artifacts[0].Reference, "1.0.0-uds.1-upstream", opts)
+	require.NoError(t, err)
+	require.Equal(t, artifacts[0].Digest, digest)
+	flavor.Version = "1.0.0-uds.1"
+	promoted, err := VerifyPublished(context.Background(), flavor, opts)
+	require.NoError(t, err)
+	require.Equal(t, digest, promoted[0].Digest)
+	flavor.Version = "1.0.0-uds.0"
+
+	_, err = Tag(context.Background(), registryHost+"/packages/testing-package:2.0.0-uds.0-upstream", "2.0.0-uds.1-upstream", opts)
+	require.ErrorIs(t, err, ErrNotPublished)
+
+	// The bundle is also required once publishBundle is set
+	require.NoError(t, os.Mkdir("bundle", 0o755))
+	require.NoError(t, os.WriteFile("bundle/uds-bundle.yaml", []byte("kind: UDSBundle\nmetadata:\n  name: testing-bundle\n"), 0o644))
+	flavor.PublishBundle = true
+	flavor.PublishBundleUrl = registryHost + "/bundles"
+
+	_, err = VerifyPublished(context.Background(), flavor, opts)
+	require.ErrorIs(t, err, ErrNotPublished)
+	require.ErrorContains(t, err, "bundles/testing-bundle:1.0.0-uds.0-upstream")
+}
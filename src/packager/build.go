@@ -0,0 +1,149 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package packager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	udsConfig "github.com/defenseunicorns/uds-cli/src/config"
+	udsBundle "github.com/defenseunicorns/uds-cli/src/pkg/bundle"
+	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+	zarfConfig "github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/lint"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	zarfPackager "github.com/zarf-dev/zarf/src/pkg/packager"
+	zarfTypes "github.com/zarf-dev/zarf/src/types"
+)
+
+// BuildOptions controls where the package and bundle for a flavor are built
+type BuildOptions struct {
+	Arch        string
+	OutputDir   string
+	BundleDir   string
+	BuildBundle bool
+	SkipSBOM    bool
+}
+
+// Build creates the Zarf package for the flavor, and optionally its bundle, returning the paths of the built artifacts
+func Build(ctx context.Context, flavor types.Flavor, opts BuildOptions) ([]string, error) {
+	var zarfPackage zarf.ZarfPackage
+	err := utils.LoadYaml("zarf.yaml", &zarfPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	if zarfPackage.Metadata.Version != flavor.Version {
+		return nil, fmt.Errorf("zarf.yaml version %s does not match flavor version %s, run update-yaml first", zarfPackage.Metadata.Version, flavor.Version)
+	}
+
+	restore, err := useBuildSettings(opts.Arch)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	message.Infof("Building package %s for flavor %s (%s)\n", zarfPackage.Metadata.Name, flavor.Name, opts.Arch)
+
+	pkgr, err := zarfPackager.New(&zarfTypes.PackagerConfig{
+		CreateOpts: zarfTypes.ZarfCreateOptions{
+			BaseDir:  ".",
+			Output:   opts.OutputDir,
+			Flavor:   flavor.Name,
+			SkipSBOM: opts.SkipSBOM,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer pkgr.ClearTempPaths()
+
+	if err := pkgr.Create(ctx); err != nil {
+		return nil, err
+	}
+
+	artifacts := []string{filepath.Join(opts.OutputDir, oci.PackageFileName(zarfPackage.Metadata.Name, opts.Arch, flavor.Version))}
+
+	if !opts.BuildBundle {
+		return artifacts, nil
+	}
+
+	for _, bundlePath := range utils.GetBundleFiles(flavor, opts.BundleDir) {
+		bundleArtifact, err := buildBundle(bundlePath, flavor, opts.Arch)
+		if err != nil {
+			return artifacts, err
+		}
+		artifacts = append(artifacts, bundleArtifact)
+	}
+
+	return artifacts, nil
+}
+
+// useBuildSettings sets the package-level settings that the Zarf and uds-cli libraries read instead of taking them in
+// their config, returning a function that restores the previous values once the build is done. The architecture
+// comes from CLIArch, confirming skips the prompts as uds-pk runs non-interactively, and the linter that validates the
+// zarf.yaml reads its schema from lint.ZarfSchema, which the Zarf CLI embeds in its main package (see zarfSchema).
+func useBuildSettings(arch string) (func(), error) {
+	schema, err := zarfSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	zarfArch, zarfConfirm := zarfConfig.CLIArch, zarfConfig.CommonOptions.Confirm
+	udsArch, udsConfirm := udsConfig.CLIArch, udsConfig.CommonOptions.Confirm
+	previousSchema := lint.ZarfSchema
+
+	zarfConfig.CLIArch, zarfConfig.CommonOptions.Confirm = arch, true
+	udsConfig.CLIArch, udsConfig.CommonOptions.Confirm = arch, true
+	lint.ZarfSchema = schema
+
+	return func() {
+		zarfConfig.CLIArch, zarfConfig.CommonOptions.Confirm = zarfArch, zarfConfirm
+		udsConfig.CLIArch, udsConfig.CommonOptions.Confirm = udsArch, udsConfirm
+		lint.ZarfSchema = previousSchema
+	}, nil
+}
+
+func buildBundle(bundlePath string, flavor types.Flavor, arch string) (string, error) {
+	var bundle uds.UDSBundle
+	err := utils.LoadYaml(bundlePath, &bundle)
+	if err != nil {
+		return "", err
+	}
+	bundleDir := filepath.Dir(bundlePath)
+
+	if bundle.Metadata.Version != flavor.Version {
+		return "", fmt.Errorf("%s version %s does not match flavor version %s, run update-yaml first", bundlePath, bundle.Metadata.Version, flavor.Version)
+	}
+
+	message.Infof("Building bundle %s for flavor %s (%s)\n", bundle.Metadata.Name, flavor.Name, arch)
+
+	bundler, err := udsBundle.New(&uds.BundleConfig{
+		CreateOpts: uds.BundleCreateOptions{
+			SourceDirectory: bundleDir,
+			Output:          bundleDir,
+			BundleFile:      filepath.Base(bundlePath),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer bundler.ClearPaths()
+
+	if err := bundler.Create(); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(bundleDir, oci.BundleFileName(bundle.Metadata.Name, arch, flavor.Version)), nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package packager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	udsConfig "github.com/defenseunicorns/uds-cli/src/config"
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/stretchr/testify/require"
+	zarfConfig "github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/lint"
+)
+
+func TestBuild(t *testing.T) {
+	testutil.Chdir(t)
+	zarfYaml := `kind: ZarfPackageConfig
+metadata:
+  name: testing-package
+  version: 1.0.0-uds.0
+components:
+  - name: empty
+    required: true
+`
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte(zarfYaml), 0o644))
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}
+
+	// The package is built for the requested architecture into the output directory, which is created
+	outputDir := filepath.Join("build", "packages")
+	artifacts, err := Build(context.Background(), flavor, BuildOptions{Arch: "arm64", OutputDir: outputDir, SkipSBOM: true})
+	require.NoError(t, err)
+	expected := filepath.Join(outputDir, "zarf-package-testing-package-arm64-1.0.0-uds.0.tar.zst")
+	require.Equal(t, []string{expected}, artifacts)
+	require.FileExists(t, expected)
+
+	// The library settings only apply to the build
+	require.Empty(t, zarfConfig.CLIArch)
+	require.False(t, zarfConfig.CommonOptions.Confirm)
+	require.Empty(t, udsConfig.CLIArch)
+	require.False(t, udsConfig.CommonOptions.Confirm)
+	require.Nil(t, lint.ZarfSchema)
+
+	// Nothing is built until update-yaml has set the flavor's version
+	flavor.Version = "1.0.0-uds.1"
+	_, err = Build(context.Background(), flavor, BuildOptions{Arch: "amd64", OutputDir: "mismatch", SkipSBOM: true})
+	require.EqualError(t, err, "zarf.yaml version 1.0.0-uds.0 does not match flavor version 1.0.0-uds.1, run update-yaml first")
+	require.NoDirExists(t, "mismatch")
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package packager
+
+import (
+	"encoding/json"
+	"io/fs"
+	"testing/fstest"
+
+	"github.com/invopop/jsonschema"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// zarfSchema returns the zarf.schema.json the Zarf linter validates the zarf.yaml against when creating a package.
+// Zarf only ships the schema as a file embedded in its main package, so it is generated here from the package types
+// of the Zarf module in go.mod the same way as hack/create-zarf-schema.sh of that Zarf version: the types are
+// reflected by `zarf internal gen-config-schema` and the jq step allows x- extensions in every object. The
+// descriptions the script takes from the Go comments are left out as they are not available at runtime and do not
+// change what is valid. Check the script when upgrading Zarf so the schema keeps matching the version that builds
+// the package.
+func zarfSchema() (fs.ReadFileFS, error) {
+	reflector := jsonschema.Reflector{ExpandedStruct: true}
+	data, err := json.Marshal(reflector.Reflect(&zarf.ZarfPackage{}))
+	if err != nil {
+		return nil, err
+	}
+
+	// Allow the x- yaml extensions in every object like the jq step
+	var schema interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	data, err = json.MarshalIndent(addExtensionProperties(schema), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return fstest.MapFS{"zarf.schema.json": &fstest.MapFile{Data: data}}, nil
+}
+
+// addExtensionProperties allows properties starting with x- in every object of the schema that has properties
+func addExtensionProperties(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = addExtensionProperties(child)
+		}
+		if _, ok := typed["properties"]; ok {
+			typed["patternProperties"] = map[string]interface{}{"^x-": map[string]interface{}{}}
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = addExtensionProperties(child)
+		}
+	}
+	return value
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package packager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZarfSchema(t *testing.T) {
+	schemaFS, err := zarfSchema()
+	require.NoError(t, err)
+	data, err := schemaFS.ReadFile("zarf.schema.json")
+	require.NoError(t, err)
+
+	var schema struct {
+		Required          []string                  `json:"required"`
+		PatternProperties map[string]any            `json:"patternProperties"`
+		Defs              map[string]map[string]any `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	require.Equal(t, []string{"kind", "components"}, schema.Required)
+	require.Contains(t, schema.PatternProperties, "^x-")
+	require.Contains(t, schema.Defs["ZarfComponent"], "patternProperties")
+	require.Equal(t, false, schema.Defs["ZarfComponent"]["additionalProperties"])
+}
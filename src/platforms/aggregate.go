@@ -0,0 +1,191 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// AggregateFlavor is a flavor listed in the aggregate release of its version
+type AggregateFlavor struct {
+	Name string
+	Tag  string
+	// References are the OCI references of the flavor's package and bundles
+	References []string
+	Released   bool
+}
+
+// aggregateAttempts is how many times a flavor saves the aggregate release when other flavors keep replacing it
+const aggregateAttempts = 5
+
+// ReleaseAggregator tags flavors and saves their aggregate release on a platform
+type ReleaseAggregator interface {
+	ReleaseCopier
+	HasTag(tag string, tokenVarName string, httpClient *http.Client) (bool, error)
+}
+
+// tagAndAggregate creates the tag of the flavor and creates or updates the aggregate release of its version. The URL
+// of the release is only returned when the tag is new so rerunning a flavor's pipeline does not announce it again.
+func tagAndAggregate(packageName string, flavor types.Flavor, releaseConfig types.ReleaseConfig, releaseNotes ReleaseNotes, platform ReleaseAggregator, tokenVarName string, httpClient *http.Client) (string, error) {
+	head, err := utils.GetRevisionCommit("HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	// Anything that can fail runs before the tag is created, as a rerun with an existing tag is not announced
+	flavors, err := aggregateFlavors(flavor, releaseConfig.Flavors, platform, tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	tag := oci.FlavorTag(flavor)
+	created, err := platform.CreateTag(tag, head.Hash.String(), tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		fmt.Printf("Tag %s already exists\n", tag)
+	}
+
+	var releaseURL string
+	for attempt := 1; ; attempt++ {
+		existing, err := platform.GetRelease(flavor.Version, tokenVarName, httpClient)
+		if err != nil {
+			return "", err
+		}
+		var existingNotes string
+		if existing != nil {
+			existingNotes = existing.Notes
+		}
+
+		release := Release{
+			Tag:    flavor.Version,
+			Name:   fmt.Sprintf("%s %s", packageName, flavor.Version),
+			Notes:  AggregateNotes(flavors, existingNotes, flavor.Name, releaseNotes),
+			Commit: head.Hash.String(),
+		}
+		releaseURL, err = platform.SaveRelease(release, tokenVarName, httpClient)
+		if err != nil {
+			return "", err
+		}
+
+		// Flavors released in parallel update the notes without a lock, so one that saved notes read before this
+		// flavor's section was added drops it. Each flavor reads the notes back and adds its section again if needed.
+		saved, err := platform.GetRelease(flavor.Version, tokenVarName, httpClient)
+		if err != nil {
+			return "", err
+		}
+		if saved != nil && sameSection(saved.Notes, release.Notes, flavor.Name) {
+			break
+		}
+		if attempt == aggregateAttempts {
+			message.Warnf("Release %s kept being replaced by other flavors, rerun the %s release to add its notes\n", flavor.Version, flavor.Name)
+			break
+		}
+		message.Warnf("Release %s was replaced by another flavor, adding the %s notes again\n", flavor.Version, flavor.Name)
+	}
+
+	if !created {
+		return "", nil
+	}
+	return releaseURL, nil
+}
+
+// aggregateFlavors returns the flavors with the same version as the flavor being released, which is released, the
+// others are released when their tag exists
+func aggregateFlavors(flavor types.Flavor, flavors []types.Flavor, platform ReleaseAggregator, tokenVarName string, httpClient *http.Client) ([]AggregateFlavor, error) {
+	var aggregate []AggregateFlavor
+	for _, other := range flavors {
+		if other.Version != flavor.Version {
+			continue
+		}
+
+		// A flavor without a publishPackageUrl is only released on the platform
+		var references []string
+		if other.PublishPackageUrl != "" {
+			var err error
+			references, err = oci.References(other)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tag := oci.FlavorTag(other)
+		released := other.Name == flavor.Name
+		if !released {
+			var err error
+			released, err = platform.HasTag(tag, tokenVarName, httpClient)
+			if err != nil {
+				message.Warnf("Unable to look up the tag %s: %s\n", tag, err)
+			}
+		}
+
+		aggregate = append(aggregate, AggregateFlavor{Name: other.Name, Tag: tag, References: references, Released: released})
+	}
+	return aggregate, nil
+}
+
+// AggregateNotes returns the notes of an aggregate release: a table of the flavors followed by the notes of each one.
+// The notes of the flavor being released are replaced and those of the other flavors are kept from the existing notes.
+// A flavor with notes in the existing notes is shown as released, as they are only added once its tag exists.
+func AggregateNotes(flavors []AggregateFlavor, existing string, flavor string, releaseNotes ReleaseNotes) string {
+	var b strings.Builder
+	b.WriteString("| Flavor | Tag | Artifacts | Status |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, f := range flavors {
+		var references []string
+		for _, reference := range f.References {
+			references = append(references, "`"+reference+"`")
+		}
+		status := "Pending"
+		if _, found := flavorSection(existing, f.Name); f.Released || found {
+			status = "Released"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", f.Name, f.Tag, strings.Join(references, "<br>"), status)
+	}
+
+	for _, f := range flavors {
+		section, found := flavorSection(existing, f.Name)
+		if f.Name == flavor {
+			section, found = fmt.Sprintf("## %s\n\n%s", f.Name, releaseNotes.String()), true
+		}
+		if !found {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s\n%s\n%s\n", sectionStart(f.Name), strings.TrimSpace(section), sectionEnd(f.Name))
+	}
+	return b.String()
+}
+
+// sameSection reports whether the flavor has the same notes in both notes of an aggregate release
+func sameSection(notes string, expected string, flavor string) bool {
+	section, found := flavorSection(notes, flavor)
+	expectedSection, _ := flavorSection(expected, flavor)
+	return found && strings.TrimSpace(section) == strings.TrimSpace(expectedSection)
+}
+
+// flavorSection returns the notes of the flavor in the notes of an aggregate release
+func flavorSection(notes string, flavor string) (string, bool) {
+	_, rest, found := strings.Cut(notes, sectionStart(flavor))
+	if !found {
+		return "", false
+	}
+	section, _, found := strings.Cut(rest, sectionEnd(flavor))
+	return section, found
+}
+
+func sectionStart(flavor string) string {
+	return fmt.Sprintf("<!-- flavor:%s -->", flavor)
+}
+
+func sectionEnd(flavor string) string {
+	return fmt.Sprintf("<!-- /flavor:%s -->", flavor)
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+)
+
+func TestAggregateNotes(t *testing.T) {
+	flavors := []AggregateFlavor{
+		{Name: "upstream", Tag: "1.0.0-uds.0-upstream", References: []string{"ghcr.io/uds/podinfo:1.0.0-uds.0-upstream", "ghcr.io/uds/bundles/podinfo:1.0.0-uds.0-upstream"}, Released: true},
+		{Name: "registry1", Tag: "1.0.0-uds.0-registry1", References: []string{"registry1.dso.mil/uds/podinfo:1.0.0-uds.0-registry1"}},
+		{Name: "unicorn", Tag: "1.0.0-uds.0-unicorn", Released: true},
+	}
+	existing := "| Flavor |\n\n<!-- flavor:upstream -->\n## upstream\n\nOld notes\n<!-- /flavor:upstream -->\n\n" +
+		"<!-- flavor:unicorn -->\n## unicorn\n\nUnicorn notes\n<!-- /flavor:unicorn -->\n"
+
+	expected := "| Flavor | Tag | Artifacts | Status |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| upstream | `1.0.0-uds.0-upstream` | `ghcr.io/uds/podinfo:1.0.0-uds.0-upstream`<br>`ghcr.io/uds/bundles/podinfo:1.0.0-uds.0-upstream` | Released |\n" +
+		"| registry1 | `1.0.0-uds.0-registry1` | `registry1.dso.mil/uds/podinfo:1.0.0-uds.0-registry1` | Pending |\n" +
+		"| unicorn | `1.0.0-uds.0-unicorn` |  | Released |\n" +
+		"\n<!-- flavor:upstream -->\n## upstream\n\nNew notes\n<!-- /flavor:upstream -->\n" +
+		"\n<!-- flavor:unicorn -->\n## unicorn\n\nUnicorn notes\n<!-- /flavor:unicorn -->\n"
+	require.Equal(t, expected, AggregateNotes(flavors, existing, "upstream", ReleaseNotes{Body: "New notes"}))
+
+	// A flavor without notes still gets a section so it is kept by the next flavor
+	notes := AggregateNotes(flavors[1:2], "", "registry1", ReleaseNotes{})
+	require.Contains(t, notes, "\n<!-- flavor:registry1 -->\n## registry1\n<!-- /flavor:registry1 -->\n")
+}
+
+func TestLoadAndTagAggregate(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	releaserYaml := `flavors:
+  - name: upstream
+    version: 1.0.0-uds.0
+    publishPackageUrl: ghcr.io/uds
+  - name: registry1
+    version: 1.0.0-uds.0
+    publishPackageUrl: ghcr.io/uds/registry1
+  - name: unicorn
+    version: 0.9.0-uds.0
+    publishPackageUrl: ghcr.io/uds/unicorn
+  - name: source
+    version: 1.0.0-uds.0
+aggregate:
+  enabled: true
+hooks:
+  postRelease:
+    - echo "$UDS_PK_TAG" >> post-release.out
+`
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	head, err := worktree.Commit("feat: initial package", &git.CommitOptions{Author: signature})
+	require.NoError(t, err)
+	t.Setenv("TEST_TOKEN", "token")
+
+	opts := ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}
+	platform := &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.0"}
+
+	// The first flavor creates its tag and the release of the version
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Nil(t, platform.released)
+	require.Equal(t, map[string]string{"1.0.0-uds.0-upstream": head.String()}, platform.tags)
+	release := platform.releases["1.0.0-uds.0"]
+	require.Equal(t, "testing-package 1.0.0-uds.0", release.Name)
+	require.Equal(t, head.String(), release.Commit)
+	require.Contains(t, release.Notes, "| upstream | `1.0.0-uds.0-upstream` | `ghcr.io/uds/testing-package:1.0.0-uds.0-upstream` | Released |\n")
+	require.Contains(t, release.Notes, "| registry1 | `1.0.0-uds.0-registry1` | `ghcr.io/uds/registry1/testing-package:1.0.0-uds.0-registry1` | Pending |\n")
+	require.Contains(t, release.Notes, "| source | `1.0.0-uds.0-source` |  | Pending |\n")
+	require.NotContains(t, release.Notes, "unicorn")
+	require.Contains(t, release.Notes, "<!-- flavor:upstream -->")
+
+	// The next flavor updates it, keeping the notes of the first
+	require.NoError(t, LoadAndTag("registry1", opts, platform))
+	release = platform.releases["1.0.0-uds.0"]
+	require.Contains(t, release.Notes, "| registry1 | `1.0.0-uds.0-registry1` | `ghcr.io/uds/registry1/testing-package:1.0.0-uds.0-registry1` | Released |\n")
+	require.Contains(t, release.Notes, "<!-- flavor:upstream -->")
+	require.Contains(t, release.Notes, "<!-- flavor:registry1 -->")
+
+	// Rerunning a flavor updates the release without running the post-release hooks again
+	require.NoError(t, LoadAndTag("registry1", opts, platform))
+	contents, err := os.ReadFile("post-release.out")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0-upstream\n1.0.0-uds.0-registry1\n", string(contents))
+}
+
+func TestLoadAndTagAggregateInvalidFlavor(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	releaserYaml := `flavors:
+  - name: upstream
+    version: 1.0.0-uds.0
+    publishPackageUrl: ghcr.io/uds
+  - name: registry1
+    version: 1.0.0-uds.0
+    publishPackageUrl: ghcr.io/uds/registry1
+    publishBundle: true
+aggregate:
+  enabled: true
+`
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("feat: initial package", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	t.Setenv("TEST_TOKEN", "token")
+
+	// The tag is not created when the aggregate release can not be built, so a fixed rerun is still announced
+	platform := &fakePlatform{}
+	err = LoadAndTag("upstream", ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}, platform)
+	require.ErrorContains(t, err, "publishBundleUrl is not set for flavor registry1")
+	require.Empty(t, platform.tags)
+	require.Empty(t, platform.releases)
+}
+
+// racingPlatform saves the concurrent release right after the next SaveRelease, like a flavor pipeline running in
+// parallel that read the aggregate release before that save
+type racingPlatform struct {
+	*fakePlatform
+	concurrent *Release
+}
+
+func (p *racingPlatform) SaveRelease(release Release, tokenVarName string, httpClient *http.Client) (string, error) {
+	releaseURL, err := p.fakePlatform.SaveRelease(release, tokenVarName, httpClient)
+	if p.concurrent != nil {
+		_, _ = p.fakePlatform.SaveRelease(*p.concurrent, tokenVarName, httpClient)
+		p.concurrent = nil
+	}
+	return releaseURL, err
+}
+
+func TestLoadAndTagAggregateConcurrent(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	releaserYaml := `flavors:
+  - name: upstream
+    version: 1.0.0-uds.0
+  - name: registry1
+    version: 1.0.0-uds.0
+aggregate:
+  enabled: true
+`
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("feat: initial package", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	t.Setenv("TEST_TOKEN", "token")
+
+	// registry1 was released at the same time from notes that did not have the upstream section yet
+	registry1 := []AggregateFlavor{{Name: "upstream", Tag: "1.0.0-uds.0-upstream"}, {Name: "registry1", Tag: "1.0.0-uds.0-registry1", Released: true}}
+	platform := &racingPlatform{
+		fakePlatform: &fakePlatform{tags: map[string]string{"1.0.0-uds.0-registry1": "abc"}},
+		concurrent:   &Release{Tag: "1.0.0-uds.0", Notes: AggregateNotes(registry1, "", "registry1", ReleaseNotes{Body: "Registry1 notes"})},
+	}
+
+	require.NoError(t, LoadAndTag("upstream", ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}, platform))
+	notes := platform.releases["1.0.0-uds.0"].Notes
+	require.Contains(t, notes, "| upstream | `1.0.0-uds.0-upstream` |  | Released |\n")
+	require.Contains(t, notes, "| registry1 | `1.0.0-uds.0-registry1` |  | Released |\n")
+	require.Contains(t, notes, "<!-- flavor:upstream -->")
+	require.Contains(t, notes, "Registry1 notes")
+}
@@ -5,11 +5,16 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
+	releasenotes "github.com/defenseunicorns/uds-pk/src/notes"
 	"github.com/defenseunicorns/uds-pk/src/platforms"
 	"github.com/defenseunicorns/uds-pk/src/types"
 	"github.com/defenseunicorns/uds-pk/src/utils"
@@ -19,51 +24,597 @@ import (
 
 type Platform struct{}
 
-func (Platform) TagAndRelease(flavor types.Flavor, tokenVarName string) error {
-	remoteURL, _, err := utils.GetRepoInfo()
+func (Platform) TagAndRelease(flavor types.Flavor, notes platforms.ReleaseNotes, tokenVarName string, httpClient *http.Client) (string, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	// Create the tag
+	zarfPackageName, err := utils.GetPackageName()
+	if err != nil {
+		return "", err
+	}
+
+	// Create the release
+	release := newFlavorRelease(flavor, zarfPackageName)
+	setReleaseNotes(context.Background(), githubClient, owner, repoName, release, notes)
+
+	message.Infof("Creating release %s-%s\n", flavor.Version, flavor.Name)
+
+	createdRelease, response, err := githubClient.Repositories.CreateRelease(context.Background(), owner, repoName, release)
+
+	err = platforms.ReleaseExists(422, response.StatusCode, err, `already_exists`, zarfPackageName, flavor)
+	if err != nil || createdRelease == nil {
+		return "", err
+	}
+	return createdRelease.GetHTMLURL(), nil
+}
+
+// newFlavorRelease returns the release of the flavor, marking release candidates as prereleases so they do not become
+// the latest release of the repository
+func newFlavorRelease(flavor types.Flavor, zarfPackageName string) *github.RepositoryRelease {
+	tagName := fmt.Sprintf("%s-%s", flavor.Version, flavor.Name)
+	_, _, isCandidate := utils.ParseReleaseCandidate(flavor.Version)
+	return &github.RepositoryRelease{
+		TagName:    github.String(tagName),
+		Name:       github.String(fmt.Sprintf("%s %s", zarfPackageName, tagName)),
+		Prerelease: github.Bool(isCandidate),
+	}
+}
+
+// setReleaseNotes sets the body of the release, letting GitHub generate the changes when the notes do not list them.
+// GitHub adds the generated changes after the body, so they are generated up front when there is a footer to keep
+// it last.
+func setReleaseNotes(ctx context.Context, githubClient *github.Client, owner string, repoName string, release *github.RepositoryRelease, notes platforms.ReleaseNotes) {
+	generate := !platforms.HasChanges(notes.Body)
+	if generate && notes.Footer != "" {
+		generated, _, err := githubClient.Repositories.GenerateReleaseNotes(ctx, owner, repoName, &github.GenerateNotesOptions{TagName: release.GetTagName()})
+		if err != nil {
+			message.Warnf("Unable to generate the release notes ahead of the footer: %s\n", err)
+		} else {
+			if notes.Body != "" {
+				notes.Body = strings.TrimSpace(notes.Body) + "\n\n"
+			}
+			notes.Body += generated.Body
+			generate = false
+		}
+	}
+
+	release.Body = github.String(platforms.ReleaseBody(release.GetName(), notes))
+	release.GenerateReleaseNotes = github.Bool(generate)
+}
+
+func (Platform) HasRelease(tag string, tokenVarName string, httpClient *http.Client) (bool, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return hasRelease(context.Background(), githubClient, owner, repoName, tag)
+}
+
+func (Platform) OpenPullRequest(pr platforms.PullRequest, tokenVarName string, httpClient *http.Client) (string, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	return openPullRequest(context.Background(), githubClient, owner, repoName, pr)
+}
+
+func (Platform) MergedPullRequests(changes releasenotes.Range, tokenVarName string, httpClient *http.Client) ([]releasenotes.PullRequest, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergedPullRequests(context.Background(), githubClient, owner, repoName, changes)
+}
+
+// mergedPullRequests pages through the closed pull requests, most recently updated first, until they were last
+// updated before the range, keeping those whose merge commit is in the range
+func mergedPullRequests(ctx context.Context, githubClient *github.Client, owner string, repoName string, changes releasenotes.Range) ([]releasenotes.PullRequest, error) {
+	listOpts := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var merged []releasenotes.PullRequest
+	for {
+		pullRequests, response, err := githubClient.PullRequests.List(ctx, owner, repoName, listOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pr := range pullRequests {
+			if !changes.Since.IsZero() && pr.GetUpdatedAt().Before(changes.Since) {
+				return merged, nil
+			}
+			if pr.MergedAt == nil || !changes.Commits[pr.GetMergeCommitSHA()] {
+				continue
+			}
+
+			var labels []string
+			for _, label := range pr.Labels {
+				labels = append(labels, label.GetName())
+			}
+			merged = append(merged, releasenotes.PullRequest{
+				Number: pr.GetNumber(),
+				Ref:    fmt.Sprintf("#%d", pr.GetNumber()),
+				Title:  pr.GetTitle(),
+				URL:    pr.GetHTMLURL(),
+				Author: pr.GetUser().GetLogin(),
+				Labels: labels,
+			})
+		}
+
+		if response.NextPage == 0 {
+			return merged, nil
+		}
+		listOpts.Page = response.NextPage
+	}
+}
+
+func (Platform) AnnounceRelease(announcement platforms.Announcement, tokenVarName string, httpClient *http.Client) error {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
 	if err != nil {
 		return err
 	}
 
-	// Create a new GitHub client
-	githubClient := github.NewClient(nil)
+	return announceRelease(context.Background(), githubClient, owner, repoName, announcement)
+}
 
-	// Set the authentication token
-	githubClient = githubClient.WithAuthToken(os.Getenv(tokenVarName))
+// announceRelease carries on when commenting on or closing an issue fails, returning every error at the end
+func announceRelease(ctx context.Context, githubClient *github.Client, owner string, repoName string, announcement platforms.Announcement) error {
+	var errs []error
+	comment := &github.IssueComment{Body: github.String(announcement.Comment)}
 
-	owner, repoName, err := getGithubOwnerAndRepo(remoteURL)
+	pullRequests := map[int]bool{}
+	for _, pr := range announcement.PullRequests {
+		if pullRequests[pr.Number] {
+			continue
+		}
+		pullRequests[pr.Number] = true
+		if announcement.Comment == "" {
+			continue
+		}
+		if _, _, err := githubClient.Issues.CreateComment(ctx, owner, repoName, pr.Number, comment); err != nil {
+			errs = append(errs, fmt.Errorf("unable to comment on pull request #%d: %w", pr.Number, err))
+			continue
+		}
+		message.Infof("Commented on pull request #%d\n", pr.Number)
+	}
+
+	for _, reference := range announcement.Issues {
+		issueOwner, issueRepo := owner, repoName
+		if reference.Project != "" {
+			var found bool
+			issueOwner, issueRepo, found = strings.Cut(reference.Project, "/")
+			if !found || strings.Contains(issueRepo, "/") {
+				errs = append(errs, fmt.Errorf("%s is not a GitHub issue reference", reference))
+				continue
+			}
+		}
+		// Pull requests share their numbers with issues, the merged ones were already commented on
+		if issueOwner == owner && issueRepo == repoName && pullRequests[reference.Number] {
+			continue
+		}
+
+		if announcement.Comment != "" {
+			if _, _, err := githubClient.Issues.CreateComment(ctx, issueOwner, issueRepo, reference.Number, comment); err != nil {
+				errs = append(errs, fmt.Errorf("unable to comment on %s: %w", reference, err))
+			} else {
+				message.Infof("Commented on %s\n", reference)
+			}
+		}
+
+		if announcement.CloseIssues {
+			if err := closeIssue(ctx, githubClient, issueOwner, issueRepo, reference); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(announcement.Milestones) > 0 {
+		if err := closeMilestone(ctx, githubClient, owner, repoName, announcement.Milestones); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeIssue closes the issue when it is still open, leaving pull requests alone
+func closeIssue(ctx context.Context, githubClient *github.Client, owner string, repoName string, reference releasenotes.Reference) error {
+	issue, _, err := githubClient.Issues.Get(ctx, owner, repoName, reference.Number)
+	if err != nil {
+		return fmt.Errorf("unable to get %s: %w", reference, err)
+	}
+	if issue.IsPullRequest() || issue.GetState() != "open" {
+		return nil
+	}
+
+	update := &github.IssueRequest{State: github.String("closed"), StateReason: github.String("completed")}
+	if _, _, err := githubClient.Issues.Edit(ctx, owner, repoName, reference.Number, update); err != nil {
+		return fmt.Errorf("unable to close %s: %w", reference, err)
+	}
+	message.Infof("Closed %s\n", reference)
+	return nil
+}
+
+// closeMilestone closes the first open milestone with one of the titles
+func closeMilestone(ctx context.Context, githubClient *github.Client, owner string, repoName string, titles []string) error {
+	listOpts := &github.MilestoneListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		milestones, response, err := githubClient.Issues.ListMilestones(ctx, owner, repoName, listOpts)
+		if err != nil {
+			return fmt.Errorf("unable to list milestones: %w", err)
+		}
+
+		for _, title := range titles {
+			for _, milestone := range milestones {
+				if milestone.GetTitle() != title {
+					continue
+				}
+				update := &github.Milestone{State: github.String("closed")}
+				if _, _, err := githubClient.Issues.EditMilestone(ctx, owner, repoName, milestone.GetNumber(), update); err != nil {
+					return fmt.Errorf("unable to close milestone %s: %w", title, err)
+				}
+				message.Infof("Closed milestone %s\n", title)
+				return nil
+			}
+		}
+
+		if response.NextPage == 0 {
+			return nil
+		}
+		listOpts.Page = response.NextPage
+	}
+}
+
+func (Platform) CreateTag(tag string, commit string, tokenVarName string, httpClient *http.Client) (bool, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return createTag(context.Background(), githubClient, owner, repoName, tag, commit)
+}
+
+func (Platform) HasTag(tag string, tokenVarName string, httpClient *http.Client) (bool, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return hasTag(context.Background(), githubClient, owner, repoName, tag)
+}
+
+func (Platform) MoveTag(tag string, commit string, tokenVarName string, httpClient *http.Client) error {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
 	if err != nil {
 		return err
 	}
 
-	// Create the tag
-	zarfPackageName, err := utils.GetPackageName()
+	return moveTag(context.Background(), githubClient, owner, repoName, tag, commit)
+}
+
+func (Platform) DeleteTag(tag string, tokenVarName string, httpClient *http.Client) error {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
 	if err != nil {
 		return err
 	}
 
-	tagName := fmt.Sprintf("%s-%s", flavor.Version, flavor.Name)
-	releaseName := fmt.Sprintf("%s %s", zarfPackageName, tagName)
+	return deleteTag(context.Background(), githubClient, owner, repoName, tag)
+}
 
-	// Create the release
-	release := &github.RepositoryRelease{
-		TagName:              github.String(tagName),
-		Name:                 github.String(releaseName),
-		Body:                 github.String(releaseName), //TODO @corang release notes
-		GenerateReleaseNotes: github.Bool(true),
+func (Platform) GetRelease(tag string, tokenVarName string, httpClient *http.Client) (*platforms.Release, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return nil, err
 	}
 
-	message.Infof("Creating release %s-%s\n", flavor.Version, flavor.Name)
+	return getRelease(context.Background(), githubClient, owner, repoName, tag)
+}
 
-	_, response, err := githubClient.Repositories.CreateRelease(context.Background(), owner, repoName, release)
+func (Platform) SaveRelease(release platforms.Release, tokenVarName string, httpClient *http.Client) (string, error) {
+	githubClient, owner, repoName, err := newRepoClient(tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
 
-	err = platforms.ReleaseExists(422, response.StatusCode, err, `already_exists`, zarfPackageName, flavor)
+	return saveRelease(context.Background(), githubClient, owner, repoName, release)
+}
+
+// createTag creates the tag reference, GitHub rejects references that already exist as unprocessable
+func createTag(ctx context.Context, githubClient *github.Client, owner string, repoName string, tag string, commit string) (bool, error) {
+	ref := &github.Reference{
+		Ref:    github.String("refs/tags/" + tag),
+		Object: &github.GitObject{SHA: github.String(commit)},
+	}
+	_, response, err := githubClient.Git.CreateRef(ctx, owner, repoName, ref)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusUnprocessableEntity && strings.Contains(err.Error(), "already exists") {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to create tag %s: %w", tag, err)
+	}
+	message.Infof("Created tag %s\n", tag)
+	return true, nil
+}
+
+func hasTag(ctx context.Context, githubClient *github.Client, owner string, repoName string, tag string) (bool, error) {
+	_, response, err := githubClient.Git.GetRef(ctx, owner, repoName, "tags/"+tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
 	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// moveTag force updates the tag reference to the commit, creating it when it does not exist yet. The release of the
+// tag follows it to the new commit.
+func moveTag(ctx context.Context, githubClient *github.Client, owner string, repoName string, tag string, commit string) error {
+	exists, err := hasTag(ctx, githubClient, owner, repoName, tag)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err := createTag(ctx, githubClient, owner, repoName, tag, commit)
+		return err
+	}
+
+	ref := &github.Reference{
+		Ref:    github.String("refs/tags/" + tag),
+		Object: &github.GitObject{SHA: github.String(commit)},
+	}
+	if _, _, err := githubClient.Git.UpdateRef(ctx, owner, repoName, ref, true); err != nil {
+		return fmt.Errorf("unable to move tag %s: %w", tag, err)
+	}
+	message.Infof("Moved tag %s to %s\n", tag, commit)
+	return nil
+}
+
+// deleteTag deletes the release of the tag before the tag itself, as GitHub keeps the release as a draft otherwise
+func deleteTag(ctx context.Context, githubClient *github.Client, owner string, repoName string, tag string) error {
+	release, response, err := githubClient.Repositories.GetReleaseByTag(ctx, owner, repoName, tag)
+	if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	if release != nil {
+		if _, err := githubClient.Repositories.DeleteRelease(ctx, owner, repoName, release.GetID()); err != nil {
+			return fmt.Errorf("unable to delete release %s: %w", tag, err)
+		}
+		message.Infof("Deleted release %s\n", tag)
+	}
+
+	exists, err := hasTag(ctx, githubClient, owner, repoName, tag)
+	if err != nil || !exists {
 		return err
 	}
+	if _, err := githubClient.Git.DeleteRef(ctx, owner, repoName, "tags/"+tag); err != nil {
+		return fmt.Errorf("unable to delete tag %s: %w", tag, err)
+	}
+	message.Infof("Deleted tag %s\n", tag)
 	return nil
 }
 
+func getRelease(ctx context.Context, githubClient *github.Client, owner string, repoName string, tag string) (*platforms.Release, error) {
+	release, response, err := githubClient.Repositories.GetReleaseByTag(ctx, owner, repoName, tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var assets []platforms.ReleaseAsset
+	for _, asset := range release.Assets {
+		assets = append(assets, platforms.ReleaseAsset{Name: asset.GetName(), URL: asset.GetURL()})
+	}
+	return &platforms.Release{
+		Tag:    release.GetTagName(),
+		Name:   release.GetName(),
+		Notes:  release.GetBody(),
+		Commit: release.GetTargetCommitish(),
+		Assets: assets,
+	}, nil
+}
+
+func saveRelease(ctx context.Context, githubClient *github.Client, owner string, repoName string, release platforms.Release) (string, error) {
+	existing, response, err := githubClient.Repositories.GetReleaseByTag(ctx, owner, repoName, release.Tag)
+	if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+		return "", err
+	}
+
+	if existing != nil {
+		update := &github.RepositoryRelease{
+			Name:       github.String(release.Name),
+			Body:       github.String(release.Notes),
+			Prerelease: github.Bool(release.Prerelease),
+		}
+		updated, _, err := githubClient.Repositories.EditRelease(ctx, owner, repoName, existing.GetID(), update)
+		if err != nil {
+			return "", err
+		}
+		message.Infof("Updated release %s\n", release.Tag)
+
+		if release.ReplaceAssets {
+			for _, asset := range existing.Assets {
+				if _, err := githubClient.Repositories.DeleteReleaseAsset(ctx, owner, repoName, asset.GetID()); err != nil {
+					return "", fmt.Errorf("unable to delete %s from release %s: %w", asset.GetName(), release.Tag, err)
+				}
+				message.Infof("Deleted %s from release %s\n", asset.GetName(), release.Tag)
+			}
+			if err := addAssets(ctx, githubClient, owner, repoName, existing.GetID(), release); err != nil {
+				return "", err
+			}
+		}
+		return updated.GetHTMLURL(), nil
+	}
+
+	newRelease := &github.RepositoryRelease{
+		TagName:    github.String(release.Tag),
+		Name:       github.String(release.Name),
+		Body:       github.String(release.Notes),
+		Prerelease: github.Bool(release.Prerelease),
+	}
+	if release.Commit != "" {
+		newRelease.TargetCommitish = github.String(release.Commit)
+	}
+	created, _, err := githubClient.Repositories.CreateRelease(ctx, owner, repoName, newRelease)
+	if err != nil {
+		return "", err
+	}
+	message.Infof("Created release %s\n", release.Tag)
+
+	if err := addAssets(ctx, githubClient, owner, repoName, created.GetID(), release); err != nil {
+		return "", err
+	}
+	return created.GetHTMLURL(), nil
+}
+
+// addAssets uploads the assets with a local path to the release and copies the others from their URL
+func addAssets(ctx context.Context, githubClient *github.Client, owner string, repoName string, releaseID int64, release platforms.Release) error {
+	for _, asset := range release.Assets {
+		if asset.Path != "" {
+			if err := uploadAsset(ctx, githubClient, owner, repoName, releaseID, asset); err != nil {
+				return fmt.Errorf("unable to upload %s to release %s: %w", asset.Path, release.Tag, err)
+			}
+			message.Infof("Uploaded %s to release %s\n", asset.Name, release.Tag)
+			continue
+		}
+
+		if err := copyAsset(ctx, githubClient, owner, repoName, releaseID, asset); err != nil {
+			return fmt.Errorf("unable to copy %s to release %s: %w", asset.Name, release.Tag, err)
+		}
+		message.Infof("Copied %s to release %s\n", asset.Name, release.Tag)
+	}
+	return nil
+}
+
+func uploadAsset(ctx context.Context, githubClient *github.Client, owner string, repoName string, releaseID int64, asset platforms.ReleaseAsset) error {
+	file, err := os.Open(asset.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, _, err = githubClient.Repositories.UploadReleaseAsset(ctx, owner, repoName, releaseID, &github.UploadOptions{Name: asset.Name}, file)
+	return err
+}
+
+// copyAsset downloads the asset through the API and uploads it to the release. GitHub only accepts uploads from a
+// file so the asset is downloaded to a temporary one.
+func copyAsset(ctx context.Context, githubClient *github.Client, owner string, repoName string, releaseID int64, asset platforms.ReleaseAsset) error {
+	req, err := githubClient.NewRequest(http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	response, err := githubClient.BareDo(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	file, err := os.CreateTemp("", "uds-pk-asset-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if _, err := io.Copy(file, response.Body); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, _, err = githubClient.Repositories.UploadReleaseAsset(ctx, owner, repoName, releaseID, &github.UploadOptions{Name: asset.Name}, file)
+	return err
+}
+
+func openPullRequest(ctx context.Context, githubClient *github.Client, owner string, repoName string, pr platforms.PullRequest) (string, error) {
+	listOpts := &github.PullRequestListOptions{State: "open", Head: owner + ":" + pr.Head, Base: pr.Base}
+	existing, _, err := githubClient.PullRequests.List(ctx, owner, repoName, listOpts)
+	if err != nil {
+		return "", err
+	}
+
+	if len(existing) > 0 {
+		update := &github.PullRequest{Title: github.String(pr.Title), Body: github.String(pr.Description)}
+		updated, _, err := githubClient.PullRequests.Edit(ctx, owner, repoName, existing[0].GetNumber(), update)
+		if err != nil {
+			return "", err
+		}
+		message.Infof("Updated pull request #%d\n", updated.GetNumber())
+		return updated.GetHTMLURL(), nil
+	}
+
+	newPullRequest := &github.NewPullRequest{
+		Title: github.String(pr.Title),
+		Head:  github.String(pr.Head),
+		Base:  github.String(pr.Base),
+		Body:  github.String(pr.Description),
+	}
+	created, _, err := githubClient.PullRequests.Create(ctx, owner, repoName, newPullRequest)
+	if err != nil {
+		return "", err
+	}
+	message.Infof("Opened pull request #%d\n", created.GetNumber())
+	return created.GetHTMLURL(), nil
+}
+
+func hasRelease(ctx context.Context, githubClient *github.Client, owner string, repoName string, tag string) (bool, error) {
+	_, response, err := githubClient.Repositories.GetReleaseByTag(ctx, owner, repoName, tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// newRepoClient creates a client for the repository of the origin remote and returns it with the repository's owner
+// and name
+func newRepoClient(tokenVarName string, httpClient *http.Client) (*github.Client, string, string, error) {
+	remoteURL, _, err := utils.GetRepoInfo()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	githubClient, err := newGithubClient(httpClient, tokenVarName)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	owner, repoName, err := getGithubOwnerAndRepo(remoteURL)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return githubClient, owner, repoName, nil
+}
+
+// newGithubClient creates a client for github.com, or for the GitHub Enterprise API set in GITHUB_API_URL
+func newGithubClient(httpClient *http.Client, tokenVarName string) (*github.Client, error) {
+	githubClient := github.NewClient(httpClient)
+
+	if token := os.Getenv(tokenVarName); token != "" {
+		githubClient = githubClient.WithAuthToken(token)
+	}
+
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" && apiURL != "https://api.github.com" {
+		return githubClient.WithEnterpriseURLs(apiURL, apiURL)
+	}
+	return githubClient, nil
+}
+
 func createGitHubTag(tagName string, releaseName string, hash string) *github.Tag {
 	tag := &github.Tag{
 		Tag:     github.String(tagName),
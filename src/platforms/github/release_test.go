@@ -4,9 +4,23 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	releasenotes "github.com/defenseunicorns/uds-pk/src/notes"
+	"github.com/defenseunicorns/uds-pk/src/platforms"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	github "github.com/google/go-github/v66/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateGitHubTag(t *testing.T) {
@@ -34,6 +48,18 @@ func TestCreateGitHubTag(t *testing.T) {
 	}
 }
 
+func TestNewFlavorRelease(t *testing.T) {
+	release := newFlavorRelease(types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}, "testing-package")
+	require.Equal(t, "1.0.0-uds.0-upstream", release.GetTagName())
+	require.Equal(t, "testing-package 1.0.0-uds.0-upstream", release.GetName())
+	require.False(t, release.GetPrerelease())
+
+	// Release candidates must not become the latest release
+	release = newFlavorRelease(types.Flavor{Name: "upstream", Version: "1.0.0-uds.1-rc.2"}, "testing-package")
+	require.Equal(t, "1.0.0-uds.1-rc.2-upstream", release.GetTagName())
+	require.True(t, release.GetPrerelease())
+}
+
 func TestGetGithubOwnerAndRepo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -94,3 +120,475 @@ func TestGetGithubOwnerAndRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestHasRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/defenseunicorns/uds-pk/releases/tags/1.0.0-uds.0-upstream":
+			fmt.Fprint(w, `{"tag_name": "1.0.0-uds.0-upstream"}`)
+		case "/api/v3/repos/defenseunicorns/uds-pk/releases/tags/1.0.0-uds.0-broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	released, err := hasRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-upstream")
+	require.NoError(t, err)
+	assert.True(t, released)
+
+	released, err = hasRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.1-uds.0-upstream")
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	_, err = hasRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-broken")
+	require.Error(t, err)
+}
+
+func TestOpenPullRequest(t *testing.T) {
+	var created, edited map[string]any
+	open := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/pulls":
+			assert.Equal(t, "defenseunicorns:release/upstream", r.URL.Query().Get("head"))
+			assert.Equal(t, "main", r.URL.Query().Get("base"))
+			if open {
+				fmt.Fprint(w, `[{"number": 7}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/pulls":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			fmt.Fprint(w, `{"number": 7, "html_url": "https://github.com/defenseunicorns/uds-pk/pull/7"}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/pulls/7":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&edited))
+			fmt.Fprint(w, `{"number": 7, "html_url": "https://github.com/defenseunicorns/uds-pk/pull/7"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	pr := platforms.PullRequest{Head: "release/upstream", Base: "main", Title: "chore: release 1.0.1-uds.0-upstream", Description: "notes"}
+	url, err := openPullRequest(context.Background(), githubClient, "defenseunicorns", "uds-pk", pr)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/defenseunicorns/uds-pk/pull/7", url)
+	assert.Equal(t, "release/upstream", created["head"])
+	assert.Equal(t, "notes", created["body"])
+	assert.Nil(t, edited)
+
+	// An open pull request is updated instead of opening another
+	open = true
+	pr.Description = "new notes"
+	_, err = openPullRequest(context.Background(), githubClient, "defenseunicorns", "uds-pk", pr)
+	require.NoError(t, err)
+	assert.Equal(t, "new notes", edited["body"])
+	assert.Equal(t, "chore: release 1.0.1-uds.0-upstream", edited["title"])
+}
+
+func TestMergedPullRequests(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v3/repos/defenseunicorns/uds-pk/pulls", r.URL.Path)
+		assert.Equal(t, "closed", r.URL.Query().Get("state"))
+		assert.Equal(t, "updated", r.URL.Query().Get("sort"))
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/defenseunicorns/uds-pk/pulls?page=2>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[
+				{"number": 12, "title": "Add sidecar support", "html_url": "https://github.com/defenseunicorns/uds-pk/pull/12",
+				 "user": {"login": "octocat"}, "labels": [{"name": "enhancement"}], "merged_at": "2024-10-02T00:00:00Z",
+				 "merge_commit_sha": "aaaa", "updated_at": "2024-10-03T00:00:00Z"},
+				{"number": 11, "title": "Closed without merging", "merge_commit_sha": "bbbb", "updated_at": "2024-10-02T00:00:00Z"}
+			]`)
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/defenseunicorns/uds-pk/pulls?page=3>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[
+				{"number": 10, "title": "Merged into another branch", "merged_at": "2024-10-02T00:00:00Z",
+				 "merge_commit_sha": "cccc", "updated_at": "2024-10-02T00:00:00Z"},
+				{"number": 9, "title": "Previous release", "merged_at": "2024-09-01T00:00:00Z",
+				 "merge_commit_sha": "dddd", "updated_at": "2024-09-01T00:00:00Z"}
+			]`)
+		default:
+			t.Errorf("requested pull requests updated before the previous release")
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	changes := releasenotes.Range{
+		Since:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Commits: map[string]bool{"aaaa": true, "bbbb": true, "dddd": true},
+	}
+	merged, err := mergedPullRequests(context.Background(), githubClient, "defenseunicorns", "uds-pk", changes)
+	require.NoError(t, err)
+	require.Equal(t, []releasenotes.PullRequest{{
+		Number: 12,
+		Ref:    "#12",
+		Title:  "Add sidecar support",
+		URL:    "https://github.com/defenseunicorns/uds-pk/pull/12",
+		Author: "octocat",
+		Labels: []string{"enhancement"},
+	}}, merged)
+}
+
+func TestSetReleaseNotes(t *testing.T) {
+	generateCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v3/repos/defenseunicorns/uds-pk/releases/generate-notes", r.URL.Path)
+		generateCalls++
+		var options map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&options))
+		assert.Equal(t, "1.0.0-uds.0-upstream", options["tag_name"])
+		fmt.Fprint(w, `{"name": "1.0.0-uds.0-upstream", "body": "## What's Changed\n* Fix the port"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	newRelease := func() *github.RepositoryRelease {
+		return &github.RepositoryRelease{TagName: github.String("1.0.0-uds.0-upstream"), Name: github.String("podinfo 1.0.0-uds.0-upstream")}
+	}
+
+	// A header is put before the changes GitHub generates when creating the release
+	release := newRelease()
+	setReleaseNotes(context.Background(), githubClient, "defenseunicorns", "uds-pk", release, platforms.ReleaseNotes{Header: "Upgrade notes", Body: "## Upstream changes\n"})
+	assert.Equal(t, "podinfo 1.0.0-uds.0-upstream\n\nUpgrade notes\n\n## Upstream changes\n", release.GetBody())
+	assert.True(t, release.GetGenerateReleaseNotes())
+	assert.Equal(t, 0, generateCalls)
+
+	// The changes are generated up front to keep a footer last
+	release = newRelease()
+	setReleaseNotes(context.Background(), githubClient, "defenseunicorns", "uds-pk", release, platforms.ReleaseNotes{Body: "## Upstream changes\n", Footer: "Upgrade notes"})
+	assert.Equal(t, "podinfo 1.0.0-uds.0-upstream\n\n## Upstream changes\n\n## What's Changed\n* Fix the port\n\nUpgrade notes\n", release.GetBody())
+	assert.False(t, release.GetGenerateReleaseNotes())
+	assert.Equal(t, 1, generateCalls)
+
+	// Nothing is generated when the notes already list the changes
+	release = newRelease()
+	setReleaseNotes(context.Background(), githubClient, "defenseunicorns", "uds-pk", release, platforms.ReleaseNotes{Body: "## Changes\n\nNo changes\n", Footer: "Upgrade notes"})
+	assert.Equal(t, "podinfo 1.0.0-uds.0-upstream\n\n## Changes\n\nNo changes\n\nUpgrade notes\n", release.GetBody())
+	assert.False(t, release.GetGenerateReleaseNotes())
+	assert.Equal(t, 1, generateCalls)
+}
+
+func TestAnnounceRelease(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/comments"):
+			var comment map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			assert.Equal(t, "Released in podinfo 1.0.0-uds.0-upstream", comment["body"])
+			if r.URL.Path == "/api/v3/repos/other/repo/issues/9/comments" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "Forbidden"}`)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/issues/3":
+			fmt.Fprint(w, `{"number": 3, "state": "open"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/issues/4":
+			fmt.Fprint(w, `{"number": 4, "state": "closed"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/other/repo/issues/9":
+			fmt.Fprint(w, `{"number": 9, "state": "open", "pull_request": {"url": "https://github.com/other/repo/pull/9"}}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/issues/3":
+			var update map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+			assert.Equal(t, "closed", update["state"])
+			fmt.Fprint(w, `{"number": 3, "state": "closed"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/milestones":
+			assert.Equal(t, "open", r.URL.Query().Get("state"))
+			fmt.Fprint(w, `[{"number": 1, "title": "0.9.0"}, {"number": 2, "title": "1.0.0-uds.0-upstream"}]`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/milestones/2":
+			fmt.Fprint(w, `{"number": 2, "state": "closed"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	announcement := platforms.Announcement{
+		Comment:      "Released in podinfo 1.0.0-uds.0-upstream",
+		Issues:       []releasenotes.Reference{{Number: 3}, {Number: 12}, {Number: 4}, {Project: "other/repo", Number: 9}, {Project: "gitlab/group/project", Number: 1}},
+		PullRequests: []releasenotes.PullRequest{{Number: 12}, {Number: 12}},
+		CloseIssues:  true,
+		Milestones:   []string{"1.0.0-uds.0", "1.0.0-uds.0-upstream"},
+	}
+	err = announceRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", announcement)
+	require.ErrorContains(t, err, "unable to comment on other/repo#9")
+	require.ErrorContains(t, err, "gitlab/group/project#1 is not a GitHub issue reference")
+
+	assert.Equal(t, []string{
+		"POST /api/v3/repos/defenseunicorns/uds-pk/issues/12/comments",
+		"POST /api/v3/repos/defenseunicorns/uds-pk/issues/3/comments",
+		"GET /api/v3/repos/defenseunicorns/uds-pk/issues/3",
+		"PATCH /api/v3/repos/defenseunicorns/uds-pk/issues/3",
+		"POST /api/v3/repos/defenseunicorns/uds-pk/issues/4/comments",
+		"GET /api/v3/repos/defenseunicorns/uds-pk/issues/4",
+		"POST /api/v3/repos/other/repo/issues/9/comments",
+		// Referenced pull requests are not closed
+		"GET /api/v3/repos/other/repo/issues/9",
+		"GET /api/v3/repos/defenseunicorns/uds-pk/milestones",
+		"PATCH /api/v3/repos/defenseunicorns/uds-pk/milestones/2",
+	}, requests)
+}
+
+func TestCreateTag(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/git/refs":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			if created["ref"] == "refs/tags/1.0.0-uds.0-upstream" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				fmt.Fprint(w, `{"message": "Reference already exists"}`)
+				return
+			}
+			fmt.Fprint(w, `{"ref": "refs/tags/1.0.0-uds.0-registry1"}`)
+		case r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/git/ref/tags/1.0.0-uds.0-upstream":
+			fmt.Fprint(w, `{"ref": "refs/tags/1.0.0-uds.0-upstream"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	tagged, err := createTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-registry1", "abc123")
+	require.NoError(t, err)
+	assert.True(t, tagged)
+	assert.Equal(t, "abc123", created["sha"])
+
+	tagged, err = createTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-upstream", "abc123")
+	require.NoError(t, err)
+	assert.False(t, tagged)
+
+	exists, err := hasTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-upstream")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = hasTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-registry1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSaveRelease(t *testing.T) {
+	var created, edited map[string]any
+	exists := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases/tags/1.0.0-uds.0":
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"id": 5, "tag_name": "1.0.0-uds.0", "name": "podinfo 1.0.0-uds.0", "body": "notes", "target_commitish": "abc123"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			fmt.Fprint(w, `{"id": 5, "html_url": "https://github.com/defenseunicorns/uds-pk/releases/tag/1.0.0-uds.0"}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases/5":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&edited))
+			fmt.Fprint(w, `{"id": 5, "html_url": "https://github.com/defenseunicorns/uds-pk/releases/tag/1.0.0-uds.0"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	release, err := getRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0")
+	require.NoError(t, err)
+	assert.Nil(t, release)
+
+	release = &platforms.Release{Tag: "1.0.0-uds.0", Name: "podinfo 1.0.0-uds.0", Notes: "notes", Commit: "abc123"}
+	url, err := saveRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", *release)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/defenseunicorns/uds-pk/releases/tag/1.0.0-uds.0", url)
+	assert.Equal(t, "abc123", created["target_commitish"])
+	assert.Nil(t, edited)
+
+	// The existing release is updated instead of creating another
+	exists = true
+	existing, err := getRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0")
+	require.NoError(t, err)
+	assert.Equal(t, release, existing)
+
+	release.Notes = "new notes"
+	_, err = saveRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", *release)
+	require.NoError(t, err)
+	assert.Equal(t, "new notes", edited["body"])
+	assert.Equal(t, "podinfo 1.0.0-uds.0", edited["name"])
+}
+
+func TestCopyAsset(t *testing.T) {
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases/assets/9":
+			assert.Equal(t, "application/octet-stream", r.Header.Get("Accept"))
+			fmt.Fprint(w, "sbom")
+		case r.Method == http.MethodPost && r.URL.Path == "/api/uploads/repos/defenseunicorns/uds-pk/releases/5/assets":
+			assert.Equal(t, "sbom.tar.gz", r.URL.Query().Get("name"))
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			uploaded = string(body)
+			fmt.Fprint(w, `{"id": 10}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	asset := platforms.ReleaseAsset{Name: "sbom.tar.gz", URL: server.URL + "/api/v3/repos/defenseunicorns/uds-pk/releases/assets/9"}
+	require.NoError(t, copyAsset(context.Background(), githubClient, "defenseunicorns", "uds-pk", 5, asset))
+	assert.Equal(t, "sbom", uploaded)
+
+	asset.URL = server.URL + "/api/v3/repos/defenseunicorns/uds-pk/releases/assets/404"
+	require.Error(t, copyAsset(context.Background(), githubClient, "defenseunicorns", "uds-pk", 5, asset))
+}
+
+func TestMoveTag(t *testing.T) {
+	var created, updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/git/ref/tags/nightly-upstream":
+			fmt.Fprint(w, `{"ref": "refs/tags/nightly-upstream"}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/git/refs/tags/nightly-upstream":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			fmt.Fprint(w, `{"ref": "refs/tags/nightly-upstream"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/git/refs":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			fmt.Fprint(w, `{"ref": "refs/tags/nightly-registry1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	// An existing tag is force updated
+	require.NoError(t, moveTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "nightly-upstream", "def456"))
+	assert.Equal(t, "def456", updated["sha"])
+	assert.Equal(t, true, updated["force"])
+	assert.Nil(t, created)
+
+	// A missing tag is created
+	require.NoError(t, moveTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "nightly-registry1", "def456"))
+	assert.Equal(t, "refs/tags/nightly-registry1", created["ref"])
+}
+
+func TestSaveReleaseReplaceAssets(t *testing.T) {
+	var edited map[string]any
+	var deleted []string
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases/tags/nightly-upstream":
+			fmt.Fprint(w, `{"id": 5, "tag_name": "nightly-upstream", "assets": [{"id": 7, "name": "old.tar.zst"}, {"id": 8, "name": "sbom.tar.gz"}]}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases/5":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&edited))
+			fmt.Fprint(w, `{"id": 5, "html_url": "https://github.com/defenseunicorns/uds-pk/releases/tag/nightly-upstream"}`)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v3/repos/defenseunicorns/uds-pk/releases/assets/"):
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/v3/repos/defenseunicorns/uds-pk/releases/assets/"))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/uploads/repos/defenseunicorns/uds-pk/releases/5/assets":
+			assert.Equal(t, "package.tar.zst", r.URL.Query().Get("name"))
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			uploaded = string(body)
+			fmt.Fprint(w, `{"id": 10}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	path := t.TempDir() + "/package.tar.zst"
+	require.NoError(t, os.WriteFile(path, []byte("package"), 0o644))
+	release := platforms.Release{
+		Tag:           "nightly-upstream",
+		Name:          "podinfo nightly-upstream",
+		Notes:         "notes",
+		Prerelease:    true,
+		Assets:        []platforms.ReleaseAsset{{Name: "package.tar.zst", Path: path}},
+		ReplaceAssets: true,
+	}
+	url, err := saveRelease(context.Background(), githubClient, "defenseunicorns", "uds-pk", release)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/defenseunicorns/uds-pk/releases/tag/nightly-upstream", url)
+	assert.Equal(t, true, edited["prerelease"])
+	assert.Equal(t, []string{"7", "8"}, deleted)
+	assert.Equal(t, "package", uploaded)
+}
+
+func TestDeleteTag(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/releases/tags/1.0.0-uds.0-rc.1-upstream":
+			fmt.Fprint(w, `{"id": 5, "tag_name": "1.0.0-uds.0-rc.1-upstream"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/defenseunicorns/uds-pk/git/ref/tags/1.0.0-uds.0-rc.1-upstream":
+			fmt.Fprint(w, `{"ref": "refs/tags/1.0.0-uds.0-rc.1-upstream"}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/v3/repos/defenseunicorns/uds-pk/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	githubClient, err := newGithubClient(server.Client(), "GITHUB_TOKEN")
+	require.NoError(t, err)
+
+	// The release is deleted before its tag
+	require.NoError(t, deleteTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-rc.1-upstream"))
+	assert.Equal(t, []string{"releases/5", "git/refs/tags/1.0.0-uds.0-rc.1-upstream"}, deleted)
+
+	// Tags that do not exist are skipped
+	deleted = nil
+	require.NoError(t, deleteTag(context.Background(), githubClient, "defenseunicorns", "uds-pk", "1.0.0-uds.0-rc.2-upstream"))
+	assert.Empty(t, deleted)
+}
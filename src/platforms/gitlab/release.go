@@ -4,12 +4,15 @@
 package gitlab
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
 
+	releasenotes "github.com/defenseunicorns/uds-pk/src/notes"
 	"github.com/defenseunicorns/uds-pk/src/platforms"
 	"github.com/defenseunicorns/uds-pk/src/types"
 	"github.com/defenseunicorns/uds-pk/src/utils"
@@ -19,54 +22,514 @@ import (
 
 type Platform struct{}
 
-func (Platform) TagAndRelease(flavor types.Flavor, tokenVarName string) error {
+func (Platform) TagAndRelease(flavor types.Flavor, notes platforms.ReleaseNotes, tokenVarName string, httpClient *http.Client) (string, error) {
 	remoteURL, defaultBranch, err := utils.GetRepoInfo()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Parse the GitLab base URL from the remote URL
 	gitlabBaseURL, err := getGitlabBaseUrl(remoteURL)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Create a new GitLab client
-	gitlabClient, err := gitlab.NewClient(os.Getenv(tokenVarName), gitlab.WithBaseURL(gitlabBaseURL))
+	gitlabClient, err := gitlab.NewClient(os.Getenv(tokenVarName), gitlab.WithBaseURL(gitlabBaseURL), gitlab.WithHTTPClient(httpClient))
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	zarfPackageName, err := utils.GetPackageName()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// setup the release options
-	releaseOpts := createReleaseOptions(zarfPackageName, flavor, defaultBranch)
+	releaseOpts := createReleaseOptions(zarfPackageName, flavor, defaultBranch, notes)
 
 	message.Infof("Creating release %s-%s\n", flavor.Version, flavor.Name)
 
 	err = platforms.VerifyEnvVar("CI_PROJECT_ID")
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Create the release
-	_, response, err := gitlabClient.Releases.CreateRelease(os.Getenv("CI_PROJECT_ID"), releaseOpts)
+	createdRelease, response, err := gitlabClient.Releases.CreateRelease(os.Getenv("CI_PROJECT_ID"), releaseOpts)
 
 	err = platforms.ReleaseExists(409, response.StatusCode, err, `message: Release already exists`, zarfPackageName, flavor)
+	if err != nil || createdRelease == nil {
+		return "", err
+	}
+	return createdRelease.Links.Self, nil
+}
+
+func (Platform) HasRelease(tag string, tokenVarName string, httpClient *http.Client) (bool, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return hasRelease(gitlabClient, projectID, tag)
+}
+
+func (Platform) OpenPullRequest(pr platforms.PullRequest, tokenVarName string, httpClient *http.Client) (string, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	return openMergeRequest(gitlabClient, projectID, pr)
+}
+
+func (Platform) MergedPullRequests(changes releasenotes.Range, tokenVarName string, httpClient *http.Client) ([]releasenotes.PullRequest, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergedRequests(gitlabClient, projectID, changes)
+}
+
+func (Platform) AnnounceRelease(announcement platforms.Announcement, tokenVarName string, httpClient *http.Client) error {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return err
+	}
+
+	return announceRelease(gitlabClient, projectID, announcement)
+}
+
+func (Platform) CreateTag(tag string, commit string, tokenVarName string, httpClient *http.Client) (bool, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return createTag(gitlabClient, projectID, tag, commit)
+}
+
+func (Platform) HasTag(tag string, tokenVarName string, httpClient *http.Client) (bool, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return hasTag(gitlabClient, projectID, tag)
+}
+
+func (Platform) MoveTag(tag string, commit string, tokenVarName string, httpClient *http.Client) error {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return err
+	}
+
+	return moveTag(gitlabClient, projectID, tag, commit)
+}
+
+func (Platform) DeleteTag(tag string, tokenVarName string, httpClient *http.Client) error {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return err
+	}
+
+	return deleteTag(gitlabClient, projectID, tag)
+}
+
+func (Platform) GetRelease(tag string, tokenVarName string, httpClient *http.Client) (*platforms.Release, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRelease(gitlabClient, projectID, tag)
+}
+
+func (Platform) SaveRelease(release platforms.Release, tokenVarName string, httpClient *http.Client) (string, error) {
+	gitlabClient, projectID, err := newProjectClient(tokenVarName, httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	return saveRelease(gitlabClient, projectID, release)
+}
+
+// newProjectClient creates a client for the CI API, or the API of the origin remote outside of CI, along with the ID
+// of the project. Outside of CI the project is looked up by its path instead of its ID.
+func newProjectClient(tokenVarName string, httpClient *http.Client) (*gitlab.Client, string, error) {
+	remoteURL, _, err := utils.GetRepoInfo()
+	if err != nil {
+		return nil, "", err
+	}
+
+	gitlabBaseURL := os.Getenv("CI_API_V4_URL")
+	if gitlabBaseURL == "" {
+		gitlabBaseURL, err = getGitlabBaseUrl(remoteURL)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	gitlabClient, err := gitlab.NewClient(os.Getenv(tokenVarName), gitlab.WithBaseURL(gitlabBaseURL), gitlab.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, "", err
+	}
+
+	projectID := os.Getenv("CI_PROJECT_ID")
+	if projectID == "" {
+		projectID, err = getGitlabProjectPath(remoteURL)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return gitlabClient, projectID, nil
+}
+
+func openMergeRequest(gitlabClient *gitlab.Client, projectID string, pr platforms.PullRequest) (string, error) {
+	listOpts := &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.Ptr("opened"),
+		SourceBranch: gitlab.Ptr(pr.Head),
+		TargetBranch: gitlab.Ptr(pr.Base),
+	}
+	existing, _, err := gitlabClient.MergeRequests.ListProjectMergeRequests(projectID, listOpts)
+	if err != nil {
+		return "", err
+	}
+
+	if len(existing) > 0 {
+		updateOpts := &gitlab.UpdateMergeRequestOptions{Title: gitlab.Ptr(pr.Title), Description: gitlab.Ptr(pr.Description)}
+		updated, _, err := gitlabClient.MergeRequests.UpdateMergeRequest(projectID, existing[0].IID, updateOpts)
+		if err != nil {
+			return "", err
+		}
+		message.Infof("Updated merge request !%d\n", updated.IID)
+		return updated.WebURL, nil
+	}
+
+	createOpts := &gitlab.CreateMergeRequestOptions{
+		Title:              gitlab.Ptr(pr.Title),
+		Description:        gitlab.Ptr(pr.Description),
+		SourceBranch:       gitlab.Ptr(pr.Head),
+		TargetBranch:       gitlab.Ptr(pr.Base),
+		RemoveSourceBranch: gitlab.Ptr(true),
+	}
+	created, _, err := gitlabClient.MergeRequests.CreateMergeRequest(projectID, createOpts)
+	if err != nil {
+		return "", err
+	}
+	message.Infof("Opened merge request !%d\n", created.IID)
+	return created.WebURL, nil
+}
+
+// mergedRequests lists the merge requests merged and last updated since the range started, keeping those whose merge,
+// squash or (for fast-forward merges) head commit is in the range
+func mergedRequests(gitlabClient *gitlab.Client, projectID string, changes releasenotes.Range) ([]releasenotes.PullRequest, error) {
+	listOpts := &gitlab.ListProjectMergeRequestsOptions{
+		State:       gitlab.Ptr("merged"),
+		OrderBy:     gitlab.Ptr("updated_at"),
+		Sort:        gitlab.Ptr("desc"),
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	if !changes.Since.IsZero() {
+		listOpts.UpdatedAfter = gitlab.Ptr(changes.Since)
+	}
+
+	var merged []releasenotes.PullRequest
+	for {
+		mergeRequests, response, err := gitlabClient.MergeRequests.ListProjectMergeRequests(projectID, listOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mr := range mergeRequests {
+			if !changes.Commits[mr.MergeCommitSHA] && !changes.Commits[mr.SquashCommitSHA] && !changes.Commits[mr.SHA] {
+				continue
+			}
+
+			pr := releasenotes.PullRequest{Number: mr.IID, Ref: fmt.Sprintf("!%d", mr.IID), Title: mr.Title, URL: mr.WebURL, Labels: mr.Labels}
+			if mr.Author != nil {
+				pr.Author = mr.Author.Username
+			}
+			merged = append(merged, pr)
+		}
+
+		if response.NextPage == 0 {
+			return merged, nil
+		}
+		listOpts.Page = response.NextPage
+	}
+}
+
+// announceRelease carries on when commenting on or closing an issue fails, returning every error at the end
+func announceRelease(gitlabClient *gitlab.Client, projectID string, announcement platforms.Announcement) error {
+	var errs []error
+
+	if announcement.Comment != "" {
+		for _, mr := range announcement.PullRequests {
+			noteOpts := &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(announcement.Comment)}
+			if _, _, err := gitlabClient.Notes.CreateMergeRequestNote(projectID, mr.Number, noteOpts); err != nil {
+				errs = append(errs, fmt.Errorf("unable to comment on merge request !%d: %w", mr.Number, err))
+				continue
+			}
+			message.Infof("Commented on merge request !%d\n", mr.Number)
+		}
+	}
+
+	for _, reference := range announcement.Issues {
+		project := projectID
+		if reference.Project != "" {
+			project = reference.Project
+		}
+
+		if announcement.Comment != "" {
+			noteOpts := &gitlab.CreateIssueNoteOptions{Body: gitlab.Ptr(announcement.Comment)}
+			if _, _, err := gitlabClient.Notes.CreateIssueNote(project, reference.Number, noteOpts); err != nil {
+				errs = append(errs, fmt.Errorf("unable to comment on %s: %w", reference, err))
+			} else {
+				message.Infof("Commented on %s\n", reference)
+			}
+		}
+
+		if announcement.CloseIssues {
+			if err := closeIssue(gitlabClient, project, reference); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(announcement.Milestones) > 0 {
+		if err := closeMilestone(gitlabClient, projectID, announcement.Milestones); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeIssue closes the issue when it is still open
+func closeIssue(gitlabClient *gitlab.Client, project string, reference releasenotes.Reference) error {
+	issue, _, err := gitlabClient.Issues.GetIssue(project, reference.Number)
+	if err != nil {
+		return fmt.Errorf("unable to get %s: %w", reference, err)
+	}
+	if issue.State != "opened" {
+		return nil
+	}
+
+	if _, _, err := gitlabClient.Issues.UpdateIssue(project, reference.Number, &gitlab.UpdateIssueOptions{StateEvent: gitlab.Ptr("close")}); err != nil {
+		return fmt.Errorf("unable to close %s: %w", reference, err)
+	}
+	message.Infof("Closed %s\n", reference)
+	return nil
+}
+
+// closeMilestone closes the first active milestone of the project with one of the titles
+func closeMilestone(gitlabClient *gitlab.Client, projectID string, titles []string) error {
+	for _, title := range titles {
+		listOpts := &gitlab.ListMilestonesOptions{Title: gitlab.Ptr(title), State: gitlab.Ptr("active")}
+		milestones, _, err := gitlabClient.Milestones.ListMilestones(projectID, listOpts)
+		if err != nil {
+			return fmt.Errorf("unable to list milestones: %w", err)
+		}
+		if len(milestones) == 0 {
+			continue
+		}
+
+		updateOpts := &gitlab.UpdateMilestoneOptions{StateEvent: gitlab.Ptr("close")}
+		if _, _, err := gitlabClient.Milestones.UpdateMilestone(projectID, milestones[0].ID, updateOpts); err != nil {
+			return fmt.Errorf("unable to close milestone %s: %w", title, err)
+		}
+		message.Infof("Closed milestone %s\n", title)
+		return nil
+	}
+	return nil
+}
+
+func hasRelease(gitlabClient *gitlab.Client, projectID string, tag string) (bool, error) {
+	_, response, err := gitlabClient.Releases.GetRelease(projectID, tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
 	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// createTag creates the tag, GitLab rejects tags that already exist as a bad request
+func createTag(gitlabClient *gitlab.Client, projectID string, tag string, commit string) (bool, error) {
+	tagOpts := &gitlab.CreateTagOptions{TagName: gitlab.Ptr(tag), Ref: gitlab.Ptr(commit)}
+	_, response, err := gitlabClient.Tags.CreateTag(projectID, tagOpts)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusBadRequest && strings.Contains(err.Error(), "already exists") {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to create tag %s: %w", tag, err)
+	}
+	message.Infof("Created tag %s\n", tag)
+	return true, nil
+}
+
+func hasTag(gitlabClient *gitlab.Client, projectID string, tag string) (bool, error) {
+	_, response, err := gitlabClient.Tags.GetTag(projectID, tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// moveTag recreates the tag on the commit as GitLab tags cannot be updated. Deleting the tag also deletes its release,
+// which has to be created again.
+func moveTag(gitlabClient *gitlab.Client, projectID string, tag string, commit string) error {
+	if err := deleteTag(gitlabClient, projectID, tag); err != nil {
 		return err
 	}
+
+	_, err := createTag(gitlabClient, projectID, tag, commit)
+	return err
+}
+
+// deleteTag deletes the tag, which also deletes its release on GitLab
+func deleteTag(gitlabClient *gitlab.Client, projectID string, tag string) error {
+	exists, err := hasTag(gitlabClient, projectID, tag)
+	if err != nil || !exists {
+		return err
+	}
+	if _, err := gitlabClient.Tags.DeleteTag(projectID, tag); err != nil {
+		return fmt.Errorf("unable to delete tag %s: %w", tag, err)
+	}
+	message.Infof("Deleted tag %s\n", tag)
 	return nil
 }
 
-func createReleaseOptions(zarfPackageName string, flavor types.Flavor, branchRef string) *gitlab.CreateReleaseOptions {
+func getRelease(gitlabClient *gitlab.Client, projectID string, tag string) (*platforms.Release, error) {
+	release, response, err := gitlabClient.Releases.GetRelease(projectID, tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var assets []platforms.ReleaseAsset
+	for _, link := range release.Assets.Links {
+		assets = append(assets, platforms.ReleaseAsset{Name: link.Name, URL: link.URL})
+	}
+	return &platforms.Release{
+		Tag:    release.TagName,
+		Name:   release.Name,
+		Notes:  release.Description,
+		Commit: release.Commit.ID,
+		Assets: assets,
+	}, nil
+}
+
+func saveRelease(gitlabClient *gitlab.Client, projectID string, release platforms.Release) (string, error) {
+	existing, err := hasRelease(gitlabClient, projectID, release.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	if existing {
+		updateOpts := &gitlab.UpdateReleaseOptions{Name: gitlab.Ptr(release.Name), Description: gitlab.Ptr(release.Notes)}
+		updated, _, err := gitlabClient.Releases.UpdateRelease(projectID, release.Tag, updateOpts)
+		if err != nil {
+			return "", err
+		}
+		message.Infof("Updated release %s\n", release.Tag)
+
+		if release.ReplaceAssets {
+			if err := replaceLinks(gitlabClient, projectID, updated, release); err != nil {
+				return "", err
+			}
+		}
+		return updated.Links.Self, nil
+	}
+
+	createOpts := &gitlab.CreateReleaseOptions{
+		Name:        gitlab.Ptr(release.Name),
+		TagName:     gitlab.Ptr(release.Tag),
+		Description: gitlab.Ptr(release.Notes),
+	}
+	if release.Commit != "" {
+		createOpts.Ref = gitlab.Ptr(release.Commit)
+	}
+	if len(release.Assets) > 0 {
+		createOpts.Assets = &gitlab.ReleaseAssetsOptions{}
+		for _, asset := range release.Assets {
+			assetURL, err := linkURL(gitlabClient, projectID, asset)
+			if err != nil {
+				return "", fmt.Errorf("unable to upload %s to release %s: %w", asset.Path, release.Tag, err)
+			}
+			link := &gitlab.ReleaseAssetLinkOptions{Name: gitlab.Ptr(asset.Name), URL: gitlab.Ptr(assetURL)}
+			createOpts.Assets.Links = append(createOpts.Assets.Links, link)
+		}
+	}
+	created, _, err := gitlabClient.Releases.CreateRelease(projectID, createOpts)
+	if err != nil {
+		return "", err
+	}
+	message.Infof("Created release %s\n", release.Tag)
+	return created.Links.Self, nil
+}
+
+// replaceLinks deletes the asset links of the existing release and links the assets of the release in their place
+func replaceLinks(gitlabClient *gitlab.Client, projectID string, existing *gitlab.Release, release platforms.Release) error {
+	for _, link := range existing.Assets.Links {
+		if _, _, err := gitlabClient.ReleaseLinks.DeleteReleaseLink(projectID, release.Tag, link.ID); err != nil {
+			return fmt.Errorf("unable to delete %s from release %s: %w", link.Name, release.Tag, err)
+		}
+		message.Infof("Deleted %s from release %s\n", link.Name, release.Tag)
+	}
+
+	for _, asset := range release.Assets {
+		assetURL, err := linkURL(gitlabClient, projectID, asset)
+		if err != nil {
+			return fmt.Errorf("unable to upload %s to release %s: %w", asset.Path, release.Tag, err)
+		}
+		linkOpts := &gitlab.CreateReleaseLinkOptions{Name: gitlab.Ptr(asset.Name), URL: gitlab.Ptr(assetURL)}
+		if _, _, err := gitlabClient.ReleaseLinks.CreateReleaseLink(projectID, release.Tag, linkOpts); err != nil {
+			return fmt.Errorf("unable to link %s to release %s: %w", asset.Name, release.Tag, err)
+		}
+		message.Infof("Linked %s to release %s\n", asset.Name, release.Tag)
+	}
+	return nil
+}
+
+// linkURL returns the URL the release links the asset to, uploading the assets with a local path to the project first.
+// Uploads are returned relative to the web URL of the project.
+func linkURL(gitlabClient *gitlab.Client, projectID string, asset platforms.ReleaseAsset) (string, error) {
+	if asset.Path == "" {
+		return asset.URL, nil
+	}
+
+	file, err := os.Open(asset.Path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	uploaded, _, err := gitlabClient.Projects.UploadFile(projectID, file, asset.Name)
+	if err != nil {
+		return "", err
+	}
+	project, _, err := gitlabClient.Projects.GetProject(projectID, nil)
+	if err != nil {
+		return "", err
+	}
+	message.Infof("Uploaded %s\n", asset.Path)
+	return strings.TrimSuffix(project.WebURL, "/") + uploaded.URL, nil
+}
+
+func createReleaseOptions(zarfPackageName string, flavor types.Flavor, branchRef string, notes platforms.ReleaseNotes) *gitlab.CreateReleaseOptions {
+	releaseName := fmt.Sprintf("%s %s-%s", zarfPackageName, flavor.Version, flavor.Name)
 	return &gitlab.CreateReleaseOptions{
-		Name:        gitlab.Ptr(fmt.Sprintf("%s %s-%s", zarfPackageName, flavor.Version, flavor.Name)),
+		Name:        gitlab.Ptr(releaseName),
 		TagName:     gitlab.Ptr(fmt.Sprintf("%s-%s", flavor.Version, flavor.Name)),
-		Description: gitlab.Ptr(fmt.Sprintf("%s %s-%s", zarfPackageName, flavor.Version, flavor.Name)),
+		Description: gitlab.Ptr(platforms.ReleaseBody(releaseName, notes)),
 		Ref:         gitlab.Ptr(branchRef),
 	}
 }
@@ -88,3 +551,22 @@ func getGitlabBaseUrl(remoteURL string) (gitlabBaseURL string, err error) {
 
 	return fmt.Sprintf("%s://%s/api/v4", parsedURL.Scheme, parsedURL.Host), nil
 }
+
+func getGitlabProjectPath(remoteURL string) (string, error) {
+	var projectPath string
+
+	parsedURL, err := url.Parse(remoteURL)
+	if err == nil && parsedURL.Host != "" {
+		projectPath = parsedURL.Path
+	} else if _, path, found := strings.Cut(remoteURL, ":"); found {
+		// scp-like SSH remotes such as git@gitlab.com:group/project.git
+		projectPath = path
+	}
+
+	projectPath = strings.TrimSuffix(strings.Trim(projectPath, "/"), ".git")
+	if !strings.Contains(projectPath, "/") {
+		return "", fmt.Errorf("could not parse GitLab project path from remote URL: %s", remoteURL)
+	}
+
+	return projectPath, nil
+}
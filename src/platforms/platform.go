@@ -4,26 +4,154 @@
 package platforms
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
-
 	"regexp"
+	"strings"
 
+	"github.com/defenseunicorns/uds-pk/src/diff"
+	"github.com/defenseunicorns/uds-pk/src/hooks"
+	"github.com/defenseunicorns/uds-pk/src/notes"
+	"github.com/defenseunicorns/uds-pk/src/notify"
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/preflight"
 	"github.com/defenseunicorns/uds-pk/src/types"
 	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
+// Platform is implemented by each forge, commands only take the narrower interface they need such as Releaser,
+// TagDeleter or PullRequestOpener
 type Platform interface {
-	TagAndRelease(flavor types.Flavor, tokenVarName string) error
+	// TagAndRelease returns the URL of the created release, or an empty string if the release already existed
+	TagAndRelease(flavor types.Flavor, notes ReleaseNotes, tokenVarName string, httpClient *http.Client) (string, error)
+	HasRelease(tag string, tokenVarName string, httpClient *http.Client) (bool, error)
+	// OpenPullRequest opens a pull (or merge) request, or updates the title and description of the one already open
+	// for the same branches, returning its URL
+	OpenPullRequest(pr PullRequest, tokenVarName string, httpClient *http.Client) (string, error)
+	// MergedPullRequests returns the pull (or merge) requests merged into one of the commits in the range, newest first
+	MergedPullRequests(changes notes.Range, tokenVarName string, httpClient *http.Client) ([]notes.PullRequest, error)
+	// AnnounceRelease comments on, and optionally closes, the issues and pull requests included in a release
+	AnnounceRelease(announcement Announcement, tokenVarName string, httpClient *http.Client) error
+	// CreateTag creates a lightweight tag on the commit, returning false if the tag already existed
+	CreateTag(tag string, commit string, tokenVarName string, httpClient *http.Client) (bool, error)
+	HasTag(tag string, tokenVarName string, httpClient *http.Client) (bool, error)
+	// MoveTag creates the tag on the commit, or moves it there when it already exists
+	MoveTag(tag string, commit string, tokenVarName string, httpClient *http.Client) error
+	// DeleteTag deletes the tag along with its release, doing nothing when the tag does not exist
+	DeleteTag(tag string, tokenVarName string, httpClient *http.Client) error
+	// GetRelease returns the release for the tag, or nil if there is none
+	GetRelease(tag string, tokenVarName string, httpClient *http.Client) (*Release, error)
+	// SaveRelease creates the release, or updates the name, notes and prerelease flag of the existing release for its
+	// tag, returning its URL
+	SaveRelease(release Release, tokenVarName string, httpClient *http.Client) (string, error)
+}
+
+// Releaser creates the release of a flavor, its aggregate release or its nightly prerelease on a platform and
+// announces it
+type Releaser interface {
+	ReleaseAggregator
+	SnapshotReleaser
+	TagAndRelease(flavor types.Flavor, notes ReleaseNotes, tokenVarName string, httpClient *http.Client) (string, error)
+	AnnounceRelease(announcement Announcement, tokenVarName string, httpClient *http.Client) error
+}
+
+// PullRequestLister lists the pull (or merge) requests merged into a range of commits on a platform
+type PullRequestLister interface {
+	MergedPullRequests(changes notes.Range, tokenVarName string, httpClient *http.Client) ([]notes.PullRequest, error)
+}
+
+// Release is a release on GitHub or GitLab
+type Release struct {
+	Tag   string
+	Name  string
+	Notes string
+	// Commit is where the tag is created when it does not exist yet
+	Commit string
+	// Prerelease marks the release as not ready for production on GitHub, GitLab has no equivalent
+	Prerelease bool
+	// Assets are uploaded (GitHub) or linked (GitLab) when the release is created
+	Assets []ReleaseAsset
+	// ReplaceAssets removes the assets of an existing release and adds the Assets in their place
+	ReplaceAssets bool
 }
 
-func LoadAndTag(releaseDir, flavor, tokenVarName string, platform Platform) error {
-	err := VerifyEnvVar(tokenVarName)
+// ReleaseAsset is a file uploaded to (GitHub) or linked from (GitLab) a release
+type ReleaseAsset struct {
+	Name string
+	// URL is where the asset is downloaded from, the API URL of the asset on GitHub
+	URL string
+	// Path is a local file to upload instead of copying the asset from the URL
+	Path string
+}
+
+// Announcement is what to do with the issues, pull requests and milestone of a release once it is created
+type Announcement struct {
+	// Comment is posted on the Issues and PullRequests, nothing is posted when it is empty
+	Comment      string
+	Issues       []notes.Reference
+	PullRequests []notes.PullRequest
+	// CloseIssues closes the Issues that are still open
+	CloseIssues bool
+	// Milestones are the titles of the open milestone to close, the first one found is closed
+	Milestones []string
+}
+
+// PullRequest is a pull request on GitHub or a merge request on GitLab
+type PullRequest struct {
+	// Head is the branch with the changes and Base is the branch they are merged into
+	Head        string
+	Base        string
+	Title       string
+	Description string
+}
+
+// ReleaseNotes are the notes of a release, the Header and Footer are the hand-written notes for the version placed
+// around the generated Body
+type ReleaseNotes struct {
+	Header string
+	Body   string
+	Footer string
+}
+
+// String joins the parts of the notes that are not empty
+func (n ReleaseNotes) String() string {
+	var parts []string
+	for _, part := range []string{n.Header, n.Body, n.Footer} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// ReleaseOptions holds the settings shared by every platform when creating a release
+type ReleaseOptions struct {
+	ReleaseDir      string
+	TokenVarName    string
+	HTTPConfig      types.HTTPConfig
+	VerifyPublished bool
+	PlainHTTP       bool
+	// SkipChecks are the preflight checks enabled in the releaser.yaml that should not run
+	SkipChecks []string
+	// Snapshot releases a snapshot of HEAD as the rolling nightly-<flavor> prerelease instead of the flavor's version
+	Snapshot bool
+	// Assets are the files uploaded to the nightly release in place of its previous assets
+	Assets []string
+}
+
+func LoadAndTag(flavor string, opts ReleaseOptions, platform Releaser) error {
+	err := VerifyEnvVar(opts.TokenVarName)
 	if err != nil {
 		return err
 	}
 
-	releaseConfig, err := utils.LoadReleaseConfig(releaseDir)
+	releaseConfig, err := utils.LoadReleaseConfig(opts.ReleaseDir)
 	if err != nil {
 		return err
 	}
@@ -33,7 +161,195 @@ func LoadAndTag(releaseDir, flavor, tokenVarName string, platform Platform) erro
 		return err
 	}
 
-	return platform.TagAndRelease(currentFlavor, tokenVarName)
+	httpConfig := utils.MergeHTTPConfig(releaseConfig.HTTP, opts.HTTPConfig)
+	httpClient, err := utils.NewHTTPClient(httpConfig)
+	if err != nil {
+		return err
+	}
+
+	if opts.Snapshot {
+		return releaseSnapshot(currentFlavor, releaseConfig, opts, platform, httpConfig, httpClient)
+	}
+
+	preflightOpts := preflight.Options{SkipChecks: opts.SkipChecks, TokenVarName: opts.TokenVarName, HTTPConfig: httpConfig}
+	if err := preflight.Run(releaseConfig.Preflight, currentFlavor, releaseConfig.Flavors, preflightOpts); err != nil {
+		return fmt.Errorf("refusing to create release: %w", err)
+	}
+
+	if opts.VerifyPublished {
+		registryOpts := oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: opts.PlainHTTP}
+		if _, err := oci.VerifyPublished(context.Background(), currentFlavor, registryOpts); err != nil {
+			return fmt.Errorf("refusing to create release: %w", err)
+		}
+	}
+
+	releaseNotes, err := generateNotes(currentFlavor, releaseConfig, platform, opts.TokenVarName, httpClient)
+	if err != nil {
+		return err
+	}
+
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return err
+	}
+
+	metadata := hooks.Metadata{Package: packageName, Flavor: currentFlavor}
+	if err := hooks.Run(context.Background(), hooks.PreRelease, releaseConfig.Hooks, metadata); err != nil {
+		return fmt.Errorf("refusing to create release: %w", err)
+	}
+
+	var releaseURL string
+	if releaseConfig.Aggregate.Enabled {
+		releaseURL, err = tagAndAggregate(packageName, currentFlavor, releaseConfig, releaseNotes, platform, opts.TokenVarName, httpClient)
+	} else {
+		releaseURL, err = platform.TagAndRelease(currentFlavor, releaseNotes, opts.TokenVarName, httpClient)
+	}
+	if err != nil || releaseURL == "" {
+		return err
+	}
+
+	// Notifications are only sent for new releases and never fail the release
+	if len(releaseConfig.Notifications) > 0 {
+		msg := notify.NewMessage(packageName, currentFlavor, releaseURL, releaseNotes.String())
+		if err := notify.Send(context.Background(), releaseConfig.Notifications, msg, httpClient); err != nil {
+			message.Warnf("Unable to send notifications: %s\n", err)
+		}
+	}
+
+	// Nor does announcing the release on its issues and pull requests
+	announcement, err := newAnnouncement(packageName, currentFlavor, releaseURL, releaseConfig, platform, opts.TokenVarName, httpClient)
+	if err == nil && announcement != nil {
+		err = platform.AnnounceRelease(*announcement, opts.TokenVarName, httpClient)
+	}
+	if err != nil {
+		message.Warnf("Unable to announce the release on its issues and pull requests: %s\n", err)
+	}
+
+	metadata.ReleaseURL = releaseURL
+	if err := hooks.Run(context.Background(), hooks.PostRelease, releaseConfig.Hooks, metadata); err != nil {
+		return fmt.Errorf("release %s was created but %w", releaseURL, err)
+	}
+	return nil
+}
+
+// generateNotes returns the changes from the configured notes source followed by the upstream changes, along with
+// any hand-written notes for the version. Failing to generate the changes is only a warning so the release is still
+// created.
+func generateNotes(flavor types.Flavor, releaseConfig types.ReleaseConfig, platform PullRequestLister, tokenVarName string, httpClient *http.Client) (ReleaseNotes, error) {
+	var releaseNotes ReleaseNotes
+	if err := notes.Validate(releaseConfig.Notes); err != nil {
+		return releaseNotes, err
+	}
+
+	if releaseConfig.Notes.Source != "" {
+		changes, err := changeSections(flavor, releaseConfig, platform, tokenVarName, httpClient)
+		if err != nil {
+			message.Warnf("Unable to list the changes for the release notes: %s\n", err)
+		} else if releaseNotes.Body, err = notes.Changes(changes); err != nil {
+			return releaseNotes, err
+		}
+	}
+
+	upstream, err := diff.UpstreamChanges(flavor, releaseConfig.Flavors)
+	if err != nil {
+		message.Warnf("Unable to generate the upstream changes for the release notes: %s\n", err)
+	}
+	if releaseNotes.Body != "" && upstream != "" {
+		releaseNotes.Body += "\n"
+	}
+	releaseNotes.Body += upstream
+
+	handWritten, err := notes.ReadFile(releaseConfig.Notes, flavor)
+	if err != nil {
+		return releaseNotes, fmt.Errorf("unable to read the release notes for %s-%s: %w", flavor.Version, flavor.Name, err)
+	}
+	if releaseConfig.Notes.Placement == notes.PlacementFooter {
+		releaseNotes.Footer = handWritten
+	} else {
+		releaseNotes.Header = handWritten
+	}
+	return releaseNotes, nil
+}
+
+// changeSections groups the commits or pull requests since the previous release of the flavor
+func changeSections(flavor types.Flavor, releaseConfig types.ReleaseConfig, platform PullRequestLister, tokenVarName string, httpClient *http.Client) ([]notes.Section, error) {
+	since, err := notes.PreviousRelease(flavor, releaseConfig.Flavors)
+	if err != nil {
+		return nil, err
+	}
+
+	if releaseConfig.Notes.Source == notes.SourceCommits {
+		commits, err := notes.CommitsSince(since)
+		if err != nil {
+			return nil, err
+		}
+		return notes.Group(commits), nil
+	}
+
+	changes, err := notes.RangeSince(since)
+	if err != nil {
+		return nil, err
+	}
+	pullRequests, err := platform.MergedPullRequests(changes, tokenVarName, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return notes.GroupPullRequests(pullRequests, releaseConfig.Notes), nil
+}
+
+// newAnnouncement returns what to do with the issues referenced by the commits since the previous release of the
+// flavor, the pull requests merged into them and the milestone of the version, or nil when nothing is configured
+func newAnnouncement(packageName string, flavor types.Flavor, releaseURL string, releaseConfig types.ReleaseConfig, platform PullRequestLister, tokenVarName string, httpClient *http.Client) (*Announcement, error) {
+	config := releaseConfig.Issues
+	if !config.Comment && !config.Close && !config.CloseMilestone {
+		return nil, nil
+	}
+
+	tag := fmt.Sprintf("%s-%s", flavor.Version, flavor.Name)
+	announcement := &Announcement{CloseIssues: config.Close}
+	if config.CloseMilestone {
+		announcement.Milestones = []string{flavor.Version, tag}
+	}
+	if !config.Comment && !config.Close {
+		return announcement, nil
+	}
+
+	since, err := notes.PreviousRelease(flavor, releaseConfig.Flavors)
+	if err != nil {
+		return nil, err
+	}
+	announcement.Issues, err = notes.ReferencesSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Comment {
+		announcement.Comment = fmt.Sprintf("Released in [%s %s](%s)", packageName, tag, releaseURL)
+
+		changes, err := notes.RangeSince(since)
+		if err != nil {
+			return nil, err
+		}
+		announcement.PullRequests, err = platform.MergedPullRequests(changes, tokenVarName, httpClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return announcement, nil
+}
+
+// HasChanges reports whether the release notes list the changes in the release, in which case the platform should
+// not generate its own
+func HasChanges(releaseNotes string) bool {
+	return strings.HasPrefix(releaseNotes, notes.ChangesHeading)
+}
+
+// ReleaseBody returns the description of a release, followed by the notes when there are any
+func ReleaseBody(releaseName string, notes ReleaseNotes) string {
+	if text := notes.String(); text != "" {
+		return releaseName + "\n\n" + text
+	}
+	return releaseName
 }
 
 func VerifyEnvVar(varName string) error {
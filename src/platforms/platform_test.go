@@ -4,11 +4,22 @@
 package platforms
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/defenseunicorns/uds-pk/src/notes"
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
 	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVerifyEnvVar(t *testing.T) {
@@ -124,3 +135,281 @@ func TestReleaseExists(t *testing.T) {
 		})
 	}
 }
+
+// fakePlatform records the release and pull request it was asked to create and returns releaseURL, and the merged
+// pull requests it was asked for and returns merged. The tags and saved releases are kept by tag.
+type fakePlatform struct {
+	releaseURL   string
+	err          error
+	released     *types.Flavor
+	pullRequest  *PullRequest
+	merged       []notes.PullRequest
+	changes      *notes.Range
+	notes        ReleaseNotes
+	announcement *Announcement
+	tags         map[string]string
+	releases     map[string]Release
+}
+
+func (p *fakePlatform) TagAndRelease(flavor types.Flavor, releaseNotes ReleaseNotes, _ string, _ *http.Client) (string, error) {
+	p.released = &flavor
+	p.notes = releaseNotes
+	return p.releaseURL, p.err
+}
+
+func (p *fakePlatform) HasRelease(string, string, *http.Client) (bool, error) {
+	return p.released != nil, nil
+}
+
+func (p *fakePlatform) OpenPullRequest(pr PullRequest, _ string, _ *http.Client) (string, error) {
+	p.pullRequest = &pr
+	return "https://example.com/pulls/1", p.err
+}
+
+func (p *fakePlatform) MergedPullRequests(changes notes.Range, _ string, _ *http.Client) ([]notes.PullRequest, error) {
+	p.changes = &changes
+	return p.merged, nil
+}
+
+func (p *fakePlatform) AnnounceRelease(announcement Announcement, _ string, _ *http.Client) error {
+	p.announcement = &announcement
+	return nil
+}
+
+func (p *fakePlatform) CreateTag(tag string, commit string, _ string, _ *http.Client) (bool, error) {
+	if _, exists := p.tags[tag]; exists || p.err != nil {
+		return false, p.err
+	}
+	if p.tags == nil {
+		p.tags = map[string]string{}
+	}
+	p.tags[tag] = commit
+	return true, nil
+}
+
+func (p *fakePlatform) HasTag(tag string, _ string, _ *http.Client) (bool, error) {
+	_, exists := p.tags[tag]
+	return exists, nil
+}
+
+func (p *fakePlatform) MoveTag(tag string, commit string, _ string, _ *http.Client) error {
+	if p.tags == nil {
+		p.tags = map[string]string{}
+	}
+	p.tags[tag] = commit
+	return p.err
+}
+
+func (p *fakePlatform) DeleteTag(tag string, _ string, _ *http.Client) error {
+	delete(p.tags, tag)
+	delete(p.releases, tag)
+	return p.err
+}
+
+func (p *fakePlatform) GetRelease(tag string, _ string, _ *http.Client) (*Release, error) {
+	if release, exists := p.releases[tag]; exists {
+		return &release, nil
+	}
+	return nil, nil
+}
+
+func (p *fakePlatform) SaveRelease(release Release, _ string, _ *http.Client) (string, error) {
+	if p.releases == nil {
+		p.releases = map[string]Release{}
+	}
+	p.releases[release.Tag] = release
+	return p.releaseURL, p.err
+}
+
+func TestLoadAndTagNotifications(t *testing.T) {
+	notifications := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifications++
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	testutil.Chdir(t)
+
+	releaserYaml := fmt.Sprintf(`flavors:
+  - name: upstream
+    version: 1.0.0-uds.0
+notifications:
+  - type: slack
+    url: %[1]s/broken
+  - type: webhook
+    url: %[1]s/webhook
+`, server.URL)
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	t.Setenv("TEST_TOKEN", "token")
+
+	opts := ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}
+
+	// A failed notification does not fail the release
+	platform := &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.0-upstream"}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Equal(t, "1.0.0-uds.0", platform.released.Version)
+	require.Equal(t, 2, notifications)
+
+	// Nothing is sent when the release already existed or could not be created
+	require.NoError(t, LoadAndTag("upstream", opts, &fakePlatform{}))
+	require.Error(t, LoadAndTag("upstream", opts, &fakePlatform{err: errors.New("forbidden")}))
+	require.Equal(t, 2, notifications)
+}
+
+func TestLoadAndTagHooks(t *testing.T) {
+	testutil.Chdir(t)
+
+	releaserYaml := `flavors:
+  - name: upstream
+    version: 1.0.0-uds.0
+    hooks:
+      postRelease:
+        - echo "$UDS_PK_RELEASE_URL" > post-release.out
+  - name: blocked
+    version: 1.0.0-uds.0
+    hooks:
+      preRelease:
+        - exit 1
+hooks:
+  preRelease:
+    - echo "$UDS_PK_TAG" >> pre-release.out
+`
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	t.Setenv("TEST_TOKEN", "token")
+
+	opts := ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}
+
+	platform := &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.0-upstream"}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.NotNil(t, platform.released)
+
+	contents, err := os.ReadFile("post-release.out")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/releases/1.0.0-uds.0-upstream\n", string(contents))
+
+	// A failing preRelease hook aborts the release
+	platform = &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.0-blocked"}
+	err = LoadAndTag("blocked", opts, platform)
+	require.ErrorContains(t, err, "refusing to create release")
+	require.Nil(t, platform.released)
+
+	contents, err = os.ReadFile("pre-release.out")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0-upstream\n1.0.0-uds.0-blocked\n", string(contents))
+}
+
+func TestLoadAndTagNotes(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	var hashes []plumbing.Hash
+	for _, subject := range []string{"feat: initial package", "fix: correct the port (#12)"} {
+		require.NoError(t, os.WriteFile("README.md", []byte(subject), 0o644))
+		_, err = worktree.Add(".")
+		require.NoError(t, err)
+		hash, err := worktree.Commit(subject, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		hashes = append(hashes, hash)
+	}
+	_, err = repo.CreateTag("1.0.0-uds.0-upstream", hashes[0], nil)
+	require.NoError(t, err)
+	t.Setenv("TEST_TOKEN", "token")
+
+	opts := ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}
+	releaserYaml := "flavors:\n  - name: upstream\n    version: 1.0.0-uds.1\nnotes:\n  source: %s\n"
+
+	// Pull requests merged since the previous release are grouped by label
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(fmt.Sprintf(releaserYaml, "pullRequests")), 0o644))
+	platform := &fakePlatform{
+		releaseURL: "https://example.com/releases/1.0.0-uds.1-upstream",
+		merged: []notes.PullRequest{
+			{Ref: "#12", Title: "Correct the port", Author: "octocat", Labels: []string{"bug"}},
+			{Ref: "#11", Title: "Bump the chart", Labels: []string{"skip-changelog"}},
+		},
+	}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Equal(t, map[string]bool{hashes[1].String(): true}, platform.changes.Commits)
+	initial, err := repo.CommitObject(hashes[0])
+	require.NoError(t, err)
+	require.True(t, initial.Committer.When.Equal(platform.changes.Since))
+	changes := "## Changes\n\n### Bug Fixes\n\n- Correct the port (#12) by @octocat\n\n### Contributors\n\n- @octocat\n"
+	require.True(t, strings.HasPrefix(platform.notes.Body, changes+"\n"), platform.notes.Body)
+	require.Contains(t, platform.notes.Body, "Changes since 1.0.0-uds.0-upstream")
+	require.True(t, HasChanges(platform.notes.Body))
+
+	// Commits since the previous release are grouped by their conventional commit type
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(fmt.Sprintf(releaserYaml, "commits")), 0o644))
+	platform = &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.1-upstream"}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Nil(t, platform.changes)
+	require.Contains(t, platform.notes.Body, "### Bug Fixes\n\n- correct the port (#12) (")
+
+	// An unknown source fails before anything is released
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(fmt.Sprintf(releaserYaml, "issues")), 0o644))
+	platform = &fakePlatform{}
+	require.ErrorContains(t, LoadAndTag("upstream", opts, platform), `unknown notes source "issues"`)
+	require.Nil(t, platform.released)
+
+	// Hand-written notes for the version are placed after the generated notes
+	require.NoError(t, os.MkdirAll("docs/release-notes", 0o755))
+	require.NoError(t, os.WriteFile("docs/release-notes/1.0.0-uds.1.md", []byte("## Upgrading\n\nRename DOMAIN.\n"), 0o644))
+	releaserYaml = "flavors:\n  - name: upstream\n    version: 1.0.0-uds.1\nnotes:\n  directory: docs/release-notes\n  placement: footer\n"
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	platform = &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.1-upstream"}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Empty(t, platform.notes.Header)
+	require.Equal(t, "## Upgrading\n\nRename DOMAIN.", platform.notes.Footer)
+	require.True(t, strings.HasSuffix(platform.notes.String(), "\n\n## Upgrading\n\nRename DOMAIN.\n"))
+}
+
+func TestLoadAndTagAnnouncement(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	for _, subject := range []string{"feat: initial package\n\nFixes #1", "fix: correct the port\n\nFixes #3", "chore: tidy\n\nCloses group/project#4 and #3"} {
+		require.NoError(t, os.WriteFile("README.md", []byte(subject), 0o644))
+		_, err = worktree.Add(".")
+		require.NoError(t, err)
+		hash, err := worktree.Commit(subject, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		if subject == "feat: initial package\n\nFixes #1" {
+			_, err = repo.CreateTag("1.0.0-uds.0-upstream", hash, nil)
+			require.NoError(t, err)
+		}
+	}
+	t.Setenv("TEST_TOKEN", "token")
+
+	releaserYaml := "flavors:\n  - name: upstream\n    version: 1.0.0-uds.1\nissues:\n  comment: true\n  close: true\n  closeMilestone: true\n"
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	opts := ReleaseOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN"}
+
+	platform := &fakePlatform{
+		releaseURL: "https://example.com/releases/1.0.0-uds.1-upstream",
+		merged:     []notes.PullRequest{{Number: 12, Ref: "#12", Title: "Correct the port"}},
+	}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Equal(t, &Announcement{
+		Comment:      "Released in [testing-package 1.0.0-uds.1-upstream](https://example.com/releases/1.0.0-uds.1-upstream)",
+		Issues:       []notes.Reference{{Number: 4, Project: "group/project"}, {Number: 3}},
+		PullRequests: platform.merged,
+		CloseIssues:  true,
+		Milestones:   []string{"1.0.0-uds.1", "1.0.0-uds.1-upstream"},
+	}, platform.announcement)
+
+	// Nothing is announced unless configured
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte("flavors:\n  - name: upstream\n    version: 1.0.0-uds.1\n"), 0o644))
+	platform = &fakePlatform{releaseURL: "https://example.com/releases/1.0.0-uds.1-upstream"}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+	require.Nil(t, platform.announcement)
+}
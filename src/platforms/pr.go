@@ -0,0 +1,179 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/defenseunicorns/uds-pk/src/changelog"
+	"github.com/defenseunicorns/uds-pk/src/notes"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/defenseunicorns/uds-pk/src/version"
+	"github.com/go-git/go-git/v5"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// PullRequestOptions holds the settings for opening a release pull request
+type PullRequestOptions struct {
+	ReleaseDir   string
+	TokenVarName string
+	HTTPConfig   types.HTTPConfig
+	// Version is the version to release, when empty the current version is bumped by Bump if it is already tagged
+	Version string
+	Bump    string
+	// Title is a Go template for the pull request title and commit message, see version.CommitData
+	Title             string
+	SigningKeyVarName string
+	// SkipChangelog leaves the flavor's changelog out of the release commit
+	SkipChangelog bool
+}
+
+// ReleaseBranch returns the branch release pull requests for the flavor are pushed to
+func ReleaseBranch(flavor string) string {
+	return "release/" + flavor
+}
+
+// PullRequestOpener opens pull (or merge) requests on a platform
+type PullRequestOpener interface {
+	OpenPullRequest(pr PullRequest, tokenVarName string, httpClient *http.Client) (string, error)
+}
+
+// OpenReleasePR commits the next version of the flavor to its release branch, updating the releaser.yaml, zarf.yaml,
+// bundles and changelog, and opens (or updates) a pull request into the current branch with the release notes as the
+// description. The current branch is checked out again afterwards. Merging the pull request makes the flavor ready
+// to be released with LoadAndTag.
+func OpenReleasePR(flavorName string, opts PullRequestOptions, platform PullRequestOpener) (string, error) {
+	err := VerifyEnvVar(opts.TokenVarName)
+	if err != nil {
+		return "", err
+	}
+
+	releaseConfig, err := utils.LoadReleaseConfig(opts.ReleaseDir)
+	if err != nil {
+		return "", err
+	}
+
+	flavor, err := utils.GetFlavorConfig(flavorName, releaseConfig)
+	if err != nil {
+		return "", err
+	}
+
+	httpConfig := utils.MergeHTTPConfig(releaseConfig.HTTP, opts.HTTPConfig)
+	httpClient, err := utils.NewHTTPClient(httpConfig)
+	if err != nil {
+		return "", err
+	}
+
+	baseBranch, err := utils.GetCurrentBranch()
+	if err != nil {
+		return "", err
+	}
+
+	flavor.Version, err = nextVersion(flavor, opts)
+	if err != nil {
+		return "", err
+	}
+	message.Infof("Preparing release %s-%s\n", flavor.Version, flavor.Name)
+
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return "", err
+	}
+
+	title, err := version.RenderCommitMessage(opts.Title, version.CommitData{
+		Package: packageName,
+		Flavor:  flavor.Name,
+		Version: flavor.Version,
+		Tag:     fmt.Sprintf("%s-%s", flavor.Version, flavor.Name),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	description, err := notes.Generate(flavor, releaseConfig.Flavors)
+	if err != nil {
+		return "", err
+	}
+
+	releaseBranch := ReleaseBranch(flavor.Name)
+	restore, err := utils.CheckoutNewBranch(releaseBranch)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := restore(); err != nil {
+			message.Warnf("Unable to check out %s again: %s\n", baseBranch, err)
+		}
+	}()
+
+	commit, err := commitRelease(flavor, releaseConfig, title, opts)
+	if err != nil {
+		return "", err
+	}
+
+	remoteURL, _, err := utils.GetRepoInfo()
+	if err != nil {
+		return "", err
+	}
+	// The release branch is recreated from the base branch every time, so it is force pushed
+	if err := utils.PushCommit(commit, releaseBranch, true, utils.GitAuth(remoteURL, opts.TokenVarName), httpConfig); err != nil {
+		return "", fmt.Errorf("unable to push to origin/%s: %w", releaseBranch, err)
+	}
+	message.Infof("Pushed %s to origin/%s\n", utils.ShortSHA(commit), releaseBranch)
+
+	pr := PullRequest{Head: releaseBranch, Base: baseBranch, Title: title, Description: description}
+	return platform.OpenPullRequest(pr, opts.TokenVarName, httpClient)
+}
+
+// nextVersion returns the requested version, or the current version bumped when it has already been tagged
+func nextVersion(flavor types.Flavor, opts PullRequestOptions) (string, error) {
+	if opts.Version != "" {
+		return opts.Version, nil
+	}
+
+	tagged, err := utils.DoesTagExist(fmt.Sprintf("%s-%s", flavor.Version, flavor.Name))
+	if err != nil {
+		return "", err
+	}
+	if !tagged {
+		return flavor.Version, nil
+	}
+	return version.Next(flavor.Version, opts.Bump)
+}
+
+// commitRelease sets the flavor's version in the releaser.yaml, runs update-yaml, writes the changelog unless it is
+// skipped and commits the changed files
+func commitRelease(flavor types.Flavor, releaseConfig types.ReleaseConfig, title string, opts PullRequestOptions) (string, error) {
+	if err := utils.SetFlavorVersion(opts.ReleaseDir, flavor.Name, flavor.Version); err != nil {
+		return "", err
+	}
+
+	if err := version.UpdateYamls(flavor, releaseConfig.Hooks); err != nil {
+		return "", err
+	}
+
+	paths, err := version.ChangedYamls(flavor)
+	if err != nil {
+		return "", err
+	}
+	paths = append(paths, filepath.ToSlash(filepath.Join(opts.ReleaseDir, "releaser.yaml")))
+
+	if !opts.SkipChangelog {
+		path, _, err := changelog.Write(flavor, releaseConfig.Flavors, releaseConfig.Changelog)
+		if err != nil {
+			return "", err
+		}
+		paths = append(paths, filepath.ToSlash(path))
+	}
+
+	commit, err := utils.CommitFiles(paths, title, opts.SigningKeyVarName)
+	if errors.Is(err, git.ErrEmptyCommit) {
+		return "", fmt.Errorf("releaser.yaml and the yamls are already at %s, there is nothing to open a pull request for", flavor.Version)
+	}
+	return commit, err
+}
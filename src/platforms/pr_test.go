@@ -0,0 +1,128 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+)
+
+func TestOpenReleasePR(t *testing.T) {
+	originDir := t.TempDir()
+	origin, err := git.PlainInit(originDir, true)
+	require.NoError(t, err)
+
+	testutil.Chdir(t)
+
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("TEST_TOKEN", "token")
+
+	repo, err := git.PlainInit(".", false)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}})
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	releaserYaml := "# release settings\nflavors:\n  - name: upstream\n    version: 1.0.0-uds.0\n"
+	zarfYaml := "kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n  version: 1.0.0-uds.0\n"
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte(zarfYaml), 0o644))
+	require.NoError(t, os.Mkdir("bundle", 0o755))
+	bundleYaml := "kind: UDSBundle\nmetadata:\n  name: test\n  version: 1.0.0-uds.0\npackages:\n  - name: testing-package\n    path: ../\n    ref: 1.0.0-uds.0\n"
+	require.NoError(t, os.WriteFile("bundle/uds-bundle.yaml", []byte(bundleYaml), 0o644))
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	for _, subject := range []string{"feat: initial package", "fix(values): correct the port"} {
+		require.NoError(t, os.WriteFile("README.md", []byte(subject), 0o644))
+		_, err = worktree.Add(".")
+		require.NoError(t, err)
+		hash, err := worktree.Commit(subject, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		if subject == "feat: initial package" {
+			_, err = repo.CreateTag("1.0.0-uds.0-upstream", hash, nil)
+			require.NoError(t, err)
+		}
+	}
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	opts := PullRequestOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN", Bump: "uds"}
+	platform := &fakePlatform{}
+	url, err := OpenReleasePR("upstream", opts, platform)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/pulls/1", url)
+
+	require.Equal(t, "release/upstream", platform.pullRequest.Head)
+	require.Equal(t, "master", platform.pullRequest.Base)
+	require.Equal(t, "chore: release testing-package 1.0.0-uds.1-upstream", platform.pullRequest.Title)
+	require.Contains(t, platform.pullRequest.Description, "### Bug Fixes\n\n- **values:** correct the port (")
+	require.NotContains(t, platform.pullRequest.Description, "initial package")
+
+	// The release branch is pushed with the new version and the original branch is checked out again
+	pushed, err := origin.Reference(plumbing.NewBranchReferenceName("release/upstream"), false)
+	require.NoError(t, err)
+	commit, err := origin.CommitObject(pushed.Hash())
+	require.NoError(t, err)
+	require.Equal(t, head.Hash(), commit.ParentHashes[0])
+	file, err := commit.File("releaser.yaml")
+	require.NoError(t, err)
+	contents, err := file.Contents()
+	require.NoError(t, err)
+	require.Equal(t, "# release settings\nflavors:\n  - name: upstream\n    version: 1.0.0-uds.1\n", contents)
+	file, err = commit.File("zarf.yaml")
+	require.NoError(t, err)
+	contents, err = file.Contents()
+	require.NoError(t, err)
+	require.Contains(t, contents, "version: 1.0.0-uds.1")
+	file, err = commit.File("bundle/uds-bundle.yaml")
+	require.NoError(t, err)
+	contents, err = file.Contents()
+	require.NoError(t, err)
+	require.Contains(t, contents, "ref: 1.0.0-uds.1")
+	file, err = commit.File("CHANGELOG.md")
+	require.NoError(t, err)
+	contents, err = file.Contents()
+	require.NoError(t, err)
+	require.Contains(t, contents, "## [1.0.0-uds.1-upstream] - ")
+	require.Contains(t, contents, "- **values:** correct the port (")
+
+	current, err := repo.Head()
+	require.NoError(t, err)
+	require.Equal(t, head.Name(), current.Name())
+	require.Equal(t, head.Hash(), current.Hash())
+	data, err := os.ReadFile("releaser.yaml")
+	require.NoError(t, err)
+	require.Equal(t, releaserYaml, string(data))
+	require.NoFileExists(t, "CHANGELOG.md")
+
+	// Running again replaces the release branch, here with an explicit version and without the changelog
+	opts.Version = "1.1.0-uds.0"
+	opts.SkipChangelog = true
+	_, err = OpenReleasePR("upstream", opts, platform)
+	require.NoError(t, err)
+	require.Equal(t, "chore: release testing-package 1.1.0-uds.0-upstream", platform.pullRequest.Title)
+	replaced, err := origin.Reference(plumbing.NewBranchReferenceName("release/upstream"), false)
+	require.NoError(t, err)
+	require.NotEqual(t, pushed.Hash(), replaced.Hash())
+	commit, err = origin.CommitObject(replaced.Hash())
+	require.NoError(t, err)
+	_, err = commit.File("CHANGELOG.md")
+	require.ErrorIs(t, err, object.ErrFileNotFound)
+
+	// Uncommitted changes are refused before anything is changed
+	require.NoError(t, os.WriteFile("README.md", []byte("dirty"), 0o644))
+	_, err = OpenReleasePR("upstream", opts, platform)
+	require.ErrorContains(t, err, "uncommitted changes to README.md")
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/defenseunicorns/uds-pk/src/version"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// PromoteOptions holds the settings for promoting a release candidate
+type PromoteOptions struct {
+	ReleaseDir   string
+	TokenVarName string
+	HTTPConfig   types.HTTPConfig
+	PlainHTTP    bool
+	// SkipOCI leaves the published package and bundles of the release candidate without the final tag
+	SkipOCI bool
+}
+
+// ReleaseCopier copies a release to a new tag on a platform
+type ReleaseCopier interface {
+	GetRelease(tag string, tokenVarName string, httpClient *http.Client) (*Release, error)
+	CreateTag(tag string, commit string, tokenVarName string, httpClient *http.Client) (bool, error)
+	SaveRelease(release Release, tokenVarName string, httpClient *http.Client) (string, error)
+}
+
+// Promote releases a <version>-rc.<N> release candidate of the flavor as <version>: the final tag is created on the
+// commit of the release candidate, its published package and bundles are tagged with the final version and its
+// release is copied with its notes and assets. The releaser.yaml, zarf.yaml and bundles are then updated to the final
+// version, which is returned with the URL of the release.
+func Promote(flavorName string, candidate string, opts PromoteOptions, platform ReleaseCopier) (types.Flavor, string, error) {
+	err := VerifyEnvVar(opts.TokenVarName)
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+
+	releaseConfig, err := utils.LoadReleaseConfig(opts.ReleaseDir)
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+
+	flavor, err := utils.GetFlavorConfig(flavorName, releaseConfig)
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+
+	httpClient, err := utils.NewHTTPClient(utils.MergeHTTPConfig(releaseConfig.HTTP, opts.HTTPConfig))
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+
+	final, _, ok := utils.ParseReleaseCandidate(candidate)
+	if !ok {
+		return types.Flavor{}, "", fmt.Errorf("%s is not a release candidate, expected <version>-rc.<N>", candidate)
+	}
+	candidateFlavor, finalFlavor := flavor, flavor
+	candidateFlavor.Version = candidate
+	finalFlavor.Version = final
+	candidateTag, finalTag := oci.FlavorTag(candidateFlavor), oci.FlavorTag(finalFlavor)
+
+	commit, err := utils.GetRevisionCommit(candidateTag)
+	if err != nil {
+		return types.Flavor{}, "", fmt.Errorf("unable to find the release candidate %s: %w", candidateTag, err)
+	}
+
+	candidateRelease, err := platform.GetRelease(candidateTag, opts.TokenVarName, httpClient)
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+	if candidateRelease == nil {
+		return types.Flavor{}, "", fmt.Errorf("there is no release for the release candidate %s", candidateTag)
+	}
+
+	if !opts.SkipOCI {
+		references, err := oci.References(candidateFlavor)
+		if err != nil {
+			return types.Flavor{}, "", err
+		}
+		registryOpts := oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: opts.PlainHTTP}
+		for _, reference := range references {
+			digest, err := oci.Tag(context.Background(), reference, finalTag, registryOpts)
+			if err != nil {
+				return types.Flavor{}, "", fmt.Errorf("unable to tag %s as %s: %w", reference, finalTag, err)
+			}
+			message.Infof("Tagged %s@%s as %s\n", reference, digest, finalTag)
+		}
+	}
+
+	created, err := platform.CreateTag(finalTag, commit.Hash.String(), opts.TokenVarName, httpClient)
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+	if !created {
+		fmt.Printf("Tag %s already exists\n", finalTag)
+	}
+
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+	release := Release{
+		Tag:    finalTag,
+		Name:   fmt.Sprintf("%s %s", packageName, finalTag),
+		Notes:  candidateRelease.Notes,
+		Commit: commit.Hash.String(),
+		Assets: candidateRelease.Assets,
+	}
+	// The notes start with the name of the release, which is the only mention of the release candidate to replace
+	if candidateRelease.Name != "" {
+		release.Notes = strings.Replace(release.Notes, candidateRelease.Name, release.Name, 1)
+	}
+	releaseURL, err := platform.SaveRelease(release, opts.TokenVarName, httpClient)
+	if err != nil {
+		return types.Flavor{}, "", err
+	}
+
+	if err := utils.SetFlavorVersion(opts.ReleaseDir, finalFlavor.Name, finalFlavor.Version); err != nil {
+		return types.Flavor{}, "", err
+	}
+	if err := version.UpdateYamls(finalFlavor, releaseConfig.Hooks); err != nil {
+		return types.Flavor{}, "", err
+	}
+	return finalFlavor, releaseURL, nil
+}
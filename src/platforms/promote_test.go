@@ -0,0 +1,91 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+)
+
+func TestPromote(t *testing.T) {
+	testutil.Chdir(t)
+	t.Setenv("TEST_TOKEN", "token")
+
+	repo, err := git.PlainInit(".", false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte("flavors:\n  - name: upstream\n    version: 1.0.0-uds.0-rc.2\n"), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n  version: 1.0.0-uds.0-rc.2\n"), 0o644))
+	require.NoError(t, os.Mkdir("bundle", 0o755))
+	bundleYaml := "kind: UDSBundle\nmetadata:\n  name: test\n  version: 1.0.0-uds.0-rc.2\npackages:\n  - name: testing-package\n    path: ../\n    ref: 1.0.0-uds.0-rc.2\n"
+	require.NoError(t, os.WriteFile("bundle/uds-bundle.yaml", []byte(bundleYaml), 0o644))
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	var hashes []plumbing.Hash
+	for _, subject := range []string{"chore: release candidate", "docs: after the release candidate"} {
+		require.NoError(t, os.WriteFile("README.md", []byte(subject), 0o644))
+		_, err = worktree.Add(".")
+		require.NoError(t, err)
+		hash, err := worktree.Commit(subject, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		hashes = append(hashes, hash)
+	}
+	_, err = repo.CreateTag("1.0.0-uds.0-rc.2-upstream", hashes[0], nil)
+	require.NoError(t, err)
+
+	opts := PromoteOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN", SkipOCI: true}
+	assets := []ReleaseAsset{{Name: "sbom.tar.gz", URL: "https://example.com/assets/1"}}
+	platform := &fakePlatform{
+		releaseURL: "https://example.com/releases/1.0.0-uds.0-upstream",
+		releases: map[string]Release{"1.0.0-uds.0-rc.2-upstream": {
+			Tag:    "1.0.0-uds.0-rc.2-upstream",
+			Name:   "testing-package 1.0.0-uds.0-rc.2-upstream",
+			Notes:  "testing-package 1.0.0-uds.0-rc.2-upstream\n\n## Changes\n\n- fix the port\n",
+			Assets: assets,
+		}},
+	}
+
+	// Versions that are not release candidates and release candidates that were never released are refused
+	_, _, err = Promote("upstream", "1.0.0-uds.0", opts, platform)
+	require.ErrorContains(t, err, "1.0.0-uds.0 is not a release candidate")
+	_, _, err = Promote("upstream", "1.0.0-uds.0-rc.1", opts, platform)
+	require.ErrorContains(t, err, "unable to find the release candidate 1.0.0-uds.0-rc.1-upstream")
+
+	// The final tag is created on the release candidate's commit rather than HEAD, with its notes and assets
+	flavor, url, err := Promote("upstream", "1.0.0-uds.0-rc.2", opts, platform)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0", flavor.Version)
+	require.Equal(t, "https://example.com/releases/1.0.0-uds.0-upstream", url)
+	require.Equal(t, hashes[0].String(), platform.tags["1.0.0-uds.0-upstream"])
+	require.Equal(t, Release{
+		Tag:    "1.0.0-uds.0-upstream",
+		Name:   "testing-package 1.0.0-uds.0-upstream",
+		Notes:  "testing-package 1.0.0-uds.0-upstream\n\n## Changes\n\n- fix the port\n",
+		Commit: hashes[0].String(),
+		Assets: assets,
+	}, platform.releases["1.0.0-uds.0-upstream"])
+
+	// The releaser.yaml and zarf.yaml are moved to the final version
+	releaserYaml, err := os.ReadFile("releaser.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "flavors:\n  - name: upstream\n    version: 1.0.0-uds.0\n", string(releaserYaml))
+	zarfYaml, err := os.ReadFile("zarf.yaml")
+	require.NoError(t, err)
+	require.Contains(t, string(zarfYaml), "version: 1.0.0-uds.0\n")
+
+	// A release candidate without a release has nothing to carry forward
+	_, err = repo.CreateTag("1.0.0-uds.1-rc.1-upstream", hashes[1], nil)
+	require.NoError(t, err)
+	_, _, err = Promote("upstream", "1.0.0-uds.1-rc.1", opts, platform)
+	require.ErrorContains(t, err, "there is no release for the release candidate 1.0.0-uds.1-rc.1-upstream")
+}
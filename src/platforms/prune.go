@@ -0,0 +1,206 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+)
+
+// TagDeleter deletes tags and their releases from a platform
+type TagDeleter interface {
+	DeleteTag(tag string, tokenVarName string, httpClient *http.Client) error
+}
+
+// PruneOptions holds the settings for pruning the prereleases of flavors
+type PruneOptions struct {
+	ReleaseDir   string
+	TokenVarName string
+	HTTPConfig   types.HTTPConfig
+	PlainHTTP    bool
+	// DryRun returns what would be deleted without deleting anything
+	DryRun bool
+	// SkipOCI leaves the published packages and bundles of the prereleases in the registry
+	SkipOCI bool
+}
+
+// PrunedRelease is a release candidate or snapshot of a flavor deleted by Prune
+type PrunedRelease struct {
+	Flavor  string
+	Version string
+	// Tag is the git tag deleted along with its release, empty when the version only has published artifacts or there
+	// is no platform
+	Tag string
+	// References are the deleted packages and bundles
+	References []string
+}
+
+// prerelease is a release candidate or snapshot of a flavor found in the git tags or the registry
+type prerelease struct {
+	version string
+	// date is zero when the version is only published and is not a snapshot
+	date time.Time
+	tag  string
+	// published holds the OCI tag of the version in each repository, by the reference of the flavor in the repository
+	published map[string]string
+}
+
+// Prune deletes the release candidates and snapshots of the flavor, or of every flavor when flavorName is empty, that the
+// prune policy in the releaser.yaml does not keep. Their tags and releases are deleted from the platform, which is
+// skipped when it is nil, and their packages and bundles from the registry. Final versions and the current version of
+// each flavor are always kept.
+func Prune(flavorName string, opts PruneOptions, platform TagDeleter) ([]PrunedRelease, error) {
+	releaseConfig, err := utils.LoadReleaseConfig(opts.ReleaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := releaseConfig.Prune
+	if policy.KeepLast <= 0 && policy.OlderThanDays <= 0 {
+		return nil, errors.New("prune.keepLast or prune.olderThanDays must be set in the releaser.yaml")
+	}
+
+	flavors := releaseConfig.Flavors
+	if flavorName != "" {
+		flavor, err := utils.GetFlavorConfig(flavorName, releaseConfig)
+		if err != nil {
+			return nil, err
+		}
+		flavors = []types.Flavor{flavor}
+	}
+
+	if platform != nil && !opts.DryRun {
+		if err := VerifyEnvVar(opts.TokenVarName); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient, err := utils.NewHTTPClient(utils.MergeHTTPConfig(releaseConfig.HTTP, opts.HTTPConfig))
+	if err != nil {
+		return nil, err
+	}
+	registryOpts := oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: opts.PlainHTTP}
+
+	var pruned []PrunedRelease
+	for _, flavor := range flavors {
+		prereleases, err := findPrereleases(flavor, releaseConfig.Flavors, !opts.SkipOCI, registryOpts)
+		if err != nil {
+			return pruned, err
+		}
+
+		for _, expired := range expiredPrereleases(flavor, prereleases, policy, time.Now()) {
+			release := PrunedRelease{Flavor: flavor.Name, Version: expired.version}
+
+			if expired.tag != "" && platform != nil {
+				release.Tag = expired.tag
+				if !opts.DryRun {
+					if err := platform.DeleteTag(expired.tag, opts.TokenVarName, httpClient); err != nil {
+						return pruned, err
+					}
+				}
+			}
+
+			for reference, tag := range expired.published {
+				deleted, err := oci.DeleteTags(context.Background(), reference, []string{tag}, opts.DryRun, registryOpts)
+				if err != nil {
+					return pruned, err
+				}
+				release.References = append(release.References, deleted...)
+			}
+			slices.Sort(release.References)
+
+			if release.Tag != "" || len(release.References) > 0 {
+				pruned = append(pruned, release)
+			}
+		}
+	}
+	return pruned, nil
+}
+
+// findPrereleases returns the release candidates and snapshots of the flavor in the git tags and, when withOCI is set,
+// in the repositories of its package and bundles
+func findPrereleases(flavor types.Flavor, flavors []types.Flavor, withOCI bool, opts oci.RegistryOptions) (map[string]*prerelease, error) {
+	prereleases := map[string]*prerelease{}
+	add := func(version string) *prerelease {
+		if _, ok := prereleases[version]; !ok {
+			prereleases[version] = &prerelease{version: version, published: map[string]string{}}
+			if _, date, ok := utils.ParseSnapshot(version, flavor.Name); ok {
+				prereleases[version].date = date
+			}
+		}
+		return prereleases[version]
+	}
+
+	tags, err := utils.GetFlavorTags(flavor.Name, flavors)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if isPrerelease(tag.Version, flavor.Name) {
+			found := add(tag.Version)
+			found.tag = tag.Name
+			found.date = tag.CommitDate
+		}
+	}
+
+	if !withOCI || flavor.PublishPackageUrl == "" {
+		return prereleases, nil
+	}
+	references, err := oci.References(flavor)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range references {
+		publishedTags, err := oci.Tags(context.Background(), reference, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range publishedTags {
+			if version, ok := utils.ParseFlavorTag(tag, flavor.Name, flavors); ok && isPrerelease(version, flavor.Name) {
+				add(version).published[reference] = tag
+			}
+		}
+	}
+	return prereleases, nil
+}
+
+// expiredPrereleases returns the prereleases beyond the policy's KeepLast newest versions that are older than its
+// OlderThanDays, newest first. The flavor's current version is never expired and prereleases without a known date are
+// only expired by KeepLast.
+func expiredPrereleases(flavor types.Flavor, prereleases map[string]*prerelease, policy types.PruneConfig, now time.Time) []*prerelease {
+	var sorted []*prerelease
+	for _, found := range prereleases {
+		sorted = append(sorted, found)
+	}
+	slices.SortFunc(sorted, func(a, b *prerelease) int {
+		return utils.CompareVersions(b.version, a.version, flavor.Name)
+	})
+
+	cutoff := now.AddDate(0, 0, -policy.OlderThanDays)
+	var expired []*prerelease
+	for i, found := range sorted {
+		if i < policy.KeepLast || found.version == flavor.Version {
+			continue
+		}
+		if policy.OlderThanDays > 0 && (found.date.IsZero() || !found.date.Before(cutoff)) {
+			continue
+		}
+		expired = append(expired, found)
+	}
+	return expired
+}
+
+// isPrerelease reports whether the version is a release candidate or a snapshot of the flavor
+func isPrerelease(version string, flavor string) bool {
+	_, _, isCandidate := utils.ParseReleaseCandidate(version)
+	_, _, isSnapshot := utils.ParseSnapshot(version, flavor)
+	return isCandidate || isSnapshot
+}
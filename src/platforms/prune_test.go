@@ -0,0 +1,131 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestPrune(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	testutil.Chdir(t)
+	t.Setenv("TEST_TOKEN", "token")
+
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+
+	repo, err := git.PlainInit(".", false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	releaserYaml := `flavors:
+  - name: upstream
+    version: 1.0.0-uds.2-rc.1
+    publishPackageUrl: oci://` + registryHost + `/packages
+prune:
+  keepLast: 1
+  olderThanDays: 30
+`
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+
+	platform := &fakePlatform{tags: map[string]string{}}
+	for _, tagged := range []struct {
+		tag     string
+		daysAgo int
+	}{
+		{tag: "1.0.0-uds.0-rc.1-upstream", daysAgo: 100},
+		{tag: "1.0.0-uds.0-upstream", daysAgo: 90},
+		{tag: "1.0.0-uds.1-rc.1-upstream", daysAgo: 60},
+		{tag: "1.0.0-uds.1-rc.2-upstream", daysAgo: 5},
+		{tag: "1.0.0-uds.2-rc.1-upstream", daysAgo: 1},
+	} {
+		signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now().AddDate(0, 0, -tagged.daysAgo)}
+		hash, err := worktree.Commit(tagged.tag, &git.CommitOptions{AllowEmptyCommits: true, Author: signature})
+		require.NoError(t, err)
+		_, err = repo.CreateTag(tagged.tag, hash, nil)
+		require.NoError(t, err)
+		platform.tags[tagged.tag] = hash.String()
+	}
+
+	repoReference := registryHost + "/packages/testing-package"
+	registryOpts := oci.RegistryOptions{PlainHTTP: true}
+	ociRepo, err := oci.NewRepository(repoReference, registryOpts)
+	require.NoError(t, err)
+	digests := map[string]string{}
+	for _, tag := range []string{
+		"1.0.0-uds.0-upstream",
+		"1.0.0-uds.1-rc.1-upstream",
+		"1.0.0-uds.0-upstream-dev.20200101.aaaaaaaa",
+		"1.0.0-uds.2-rc.1-upstream-dev." + time.Now().UTC().Format("20060102") + ".bbbbbbbb",
+	} {
+		packOpts := oras.PackManifestOptions{ManifestAnnotations: map[string]string{"tag": tag}}
+		desc, err := oras.PackManifest(context.Background(), ociRepo, oras.PackManifestVersion1_1, "application/vnd.uds.test", packOpts)
+		require.NoError(t, err)
+		require.NoError(t, ociRepo.Tag(context.Background(), desc, tag))
+		digests[tag] = desc.Digest.String()
+	}
+
+	// Final versions, the current version, the newest prerelease and the prereleases from the last 30 days are kept
+	expected := []PrunedRelease{
+		{Flavor: "upstream", Version: "1.0.0-uds.1-rc.1", Tag: "1.0.0-uds.1-rc.1-upstream", References: []string{repoReference + ":1.0.0-uds.1-rc.1-upstream"}},
+		{Flavor: "upstream", Version: "1.0.0-uds.0-upstream-dev.20200101.aaaaaaaa", References: []string{repoReference + ":1.0.0-uds.0-upstream-dev.20200101.aaaaaaaa"}},
+		{Flavor: "upstream", Version: "1.0.0-uds.0-rc.1", Tag: "1.0.0-uds.0-rc.1-upstream"},
+	}
+
+	// A dry run lists what would be deleted without deleting it
+	opts := PruneOptions{ReleaseDir: ".", TokenVarName: "TEST_TOKEN", PlainHTTP: true, DryRun: true}
+	pruned, err := Prune("upstream", opts, platform)
+	require.NoError(t, err)
+	require.Equal(t, expected, pruned)
+	require.Len(t, platform.tags, 5)
+	for tag, digest := range digests {
+		_, err = ociRepo.Resolve(context.Background(), digest)
+		require.NoError(t, err, tag)
+	}
+
+	opts.DryRun = false
+	pruned, err = Prune("", opts, platform)
+	require.NoError(t, err)
+	require.Equal(t, expected, pruned)
+	require.ElementsMatch(t, []string{"1.0.0-uds.0-upstream", "1.0.0-uds.1-rc.2-upstream", "1.0.0-uds.2-rc.1-upstream"}, keys(platform.tags))
+	for tag, digest := range digests {
+		_, err = ociRepo.Resolve(context.Background(), digest)
+		if tag == "1.0.0-uds.1-rc.1-upstream" || tag == "1.0.0-uds.0-upstream-dev.20200101.aaaaaaaa" {
+			require.Error(t, err, tag)
+		} else {
+			require.NoError(t, err, tag)
+		}
+	}
+
+	// A policy is required
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte("flavors:\n  - name: upstream\n    version: 1.0.0-uds.2-rc.1\n"), 0o644))
+	_, err = Prune("upstream", opts, platform)
+	require.ErrorContains(t, err, "prune.keepLast or prune.olderThanDays must be set")
+}
+
+func keys(m map[string]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
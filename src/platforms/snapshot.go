@@ -0,0 +1,112 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/preflight"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// SnapshotReleaser moves the nightly tag of a flavor and saves its prerelease on a platform
+type SnapshotReleaser interface {
+	PullRequestLister
+	MoveTag(tag string, commit string, tokenVarName string, httpClient *http.Client) error
+	SaveRelease(release Release, tokenVarName string, httpClient *http.Client) (string, error)
+}
+
+// releaseSnapshot creates or moves the rolling nightly-<flavor> tag to HEAD and updates its prerelease with the notes
+// of the <version>-<flavor>-dev.<date>.<shortsha> snapshot, replacing its assets, then deletes the snapshots older than the
+// configured retention from the registry. Snapshots are not announced and do not run the release hooks.
+func releaseSnapshot(flavor types.Flavor, releaseConfig types.ReleaseConfig, opts ReleaseOptions, platform SnapshotReleaser, httpConfig types.HTTPConfig, httpClient *http.Client) error {
+	head, err := utils.GetRevisionCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	snapshot := flavor
+	snapshot.Version = utils.SnapshotVersion(flavor.Version, flavor.Name, head)
+
+	// The zarf.yaml and bundles carry the uncommitted snapshot version and snapshots do not bump the version
+	skipChecks := append(slices.Clone(opts.SkipChecks), preflight.CleanWorktree, preflight.VersionIncrement)
+	preflightOpts := preflight.Options{SkipChecks: skipChecks, TokenVarName: opts.TokenVarName, HTTPConfig: httpConfig}
+	if err := preflight.Run(releaseConfig.Preflight, snapshot, releaseConfig.Flavors, preflightOpts); err != nil {
+		return fmt.Errorf("refusing to release snapshot: %w", err)
+	}
+
+	registryOpts := oci.RegistryOptions{HTTPClient: httpClient, PlainHTTP: opts.PlainHTTP}
+	if opts.VerifyPublished {
+		if _, err := oci.VerifyPublished(context.Background(), snapshot, registryOpts); err != nil {
+			return fmt.Errorf("refusing to release snapshot: %w", err)
+		}
+	}
+
+	releaseNotes, err := generateNotes(snapshot, releaseConfig, platform, opts.TokenVarName, httpClient)
+	if err != nil {
+		return err
+	}
+	releaseNotes.Header = strings.TrimSpace(snapshotSummary(snapshot, head.Hash.String()) + "\n\n" + releaseNotes.Header)
+
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return err
+	}
+
+	tag := utils.NightlyTag(flavor.Name)
+	if err := platform.MoveTag(tag, head.Hash.String(), opts.TokenVarName, httpClient); err != nil {
+		return err
+	}
+
+	releaseName := fmt.Sprintf("%s %s", packageName, tag)
+	release := Release{
+		Tag:           tag,
+		Name:          releaseName,
+		Notes:         ReleaseBody(releaseName, releaseNotes),
+		Commit:        head.Hash.String(),
+		Prerelease:    true,
+		ReplaceAssets: true,
+	}
+	for _, path := range opts.Assets {
+		release.Assets = append(release.Assets, ReleaseAsset{Name: filepath.Base(path), Path: path})
+	}
+	releaseURL, err := platform.SaveRelease(release, opts.TokenVarName, httpClient)
+	if err != nil {
+		return err
+	}
+	message.Infof("Released snapshot %s as %s\n", oci.FlavorTag(snapshot), releaseURL)
+
+	// Pruning never fails the release as the next snapshot will try again
+	if days := releaseConfig.Snapshot.RetentionDays; days > 0 {
+		cutoff := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -days)
+		if _, err := oci.PruneSnapshots(context.Background(), snapshot, cutoff, registryOpts); err != nil {
+			message.Warnf("Unable to prune the snapshots older than %d days: %s\n", days, err)
+		}
+	}
+	return nil
+}
+
+// snapshotSummary describes the snapshot of the flavor built from the commit and where it is published
+func snapshotSummary(snapshot types.Flavor, commit string) string {
+	summary := fmt.Sprintf("Snapshot `%s` of the %s flavor built from %s.", snapshot.Version, snapshot.Name, commit)
+
+	// A flavor without a publishPackageUrl is only released on the platform
+	references, err := oci.References(snapshot)
+	if err != nil || len(references) == 0 {
+		return summary
+	}
+	summary += "\n\nPublished as:\n"
+	for _, reference := range references {
+		summary += fmt.Sprintf("\n- `%s`", reference)
+	}
+	return summary
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package platforms
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+)
+
+func TestLoadAndTagSnapshot(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	releaserYaml := `flavors:
+  - name: upstream
+    version: 1.0.0-uds.0
+    publishPackageUrl: ghcr.io/uds
+preflight:
+  cleanWorktree: true
+  versionIncrement: true
+hooks:
+  postRelease:
+    - echo "$UDS_PK_TAG" >> post-release.out
+`
+	require.NoError(t, os.WriteFile("releaser.yaml", []byte(releaserYaml), 0o644))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n"), 0o644))
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	head, err := worktree.Commit("feat: initial package", &git.CommitOptions{Author: signature})
+	require.NoError(t, err)
+	// The version is already released and the worktree has the uncommitted snapshot build, which snapshots allow
+	_, err = repo.CreateTag("1.0.0-uds.0-upstream", head, nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("zarf-package-testing-package-amd64.tar.zst", []byte("package"), 0o644))
+	t.Setenv("TEST_TOKEN", "token")
+
+	opts := ReleaseOptions{
+		ReleaseDir:   ".",
+		TokenVarName: "TEST_TOKEN",
+		Snapshot:     true,
+		Assets:       []string{"zarf-package-testing-package-amd64.tar.zst"},
+	}
+	platform := &fakePlatform{
+		releaseURL: "https://example.com/releases/nightly-upstream",
+		tags:       map[string]string{"nightly-upstream": "0000000"},
+	}
+	require.NoError(t, LoadAndTag("upstream", opts, platform))
+
+	// The nightly tag is moved to HEAD without tagging the snapshot version
+	require.Nil(t, platform.released)
+	require.Equal(t, map[string]string{"nightly-upstream": head.String()}, platform.tags)
+
+	snapshotVersion := "1.0.0-uds.0-upstream-dev.20261019." + head.String()[:8]
+	release := platform.releases["nightly-upstream"]
+	require.Equal(t, "testing-package nightly-upstream", release.Name)
+	require.Equal(t, head.String(), release.Commit)
+	require.True(t, release.Prerelease)
+	require.True(t, release.ReplaceAssets)
+	require.Equal(t, []ReleaseAsset{{Name: "zarf-package-testing-package-amd64.tar.zst", Path: "zarf-package-testing-package-amd64.tar.zst"}}, release.Assets)
+	require.Contains(t, release.Notes, "Snapshot `"+snapshotVersion+"` of the upstream flavor built from "+head.String()+".")
+	require.Contains(t, release.Notes, "- `ghcr.io/uds/testing-package:"+snapshotVersion+"`")
+
+	// Snapshots do not run the release hooks
+	_, err = os.Stat("post-release.out")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
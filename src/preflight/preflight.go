@@ -0,0 +1,174 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/defenseunicorns/uds-pk/src/version"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// Names of the checks, as accepted by --skip-check
+const (
+	CleanWorktree    = "clean-worktree"
+	Branch           = "branch"
+	UpToDate         = "up-to-date"
+	YamlVersions     = "yaml-versions"
+	VersionIncrement = "version-increment"
+	// All skips every check
+	All = "all"
+)
+
+// Checks lists the names of the checks in the order they run
+var Checks = []string{CleanWorktree, Branch, UpToDate, YamlVersions, VersionIncrement}
+
+// Options holds the settings used by the checks
+type Options struct {
+	// SkipChecks are the names of enabled checks that should not run
+	SkipChecks   []string
+	TokenVarName string
+	HTTPConfig   types.HTTPConfig
+}
+
+// Run runs the checks enabled in the config that are not skipped, returning every failure together
+func Run(config types.PreflightConfig, flavor types.Flavor, flavors []types.Flavor, opts Options) error {
+	skipped := map[string]bool{}
+	for _, name := range opts.SkipChecks {
+		if name != All && !slices.Contains(Checks, name) {
+			return fmt.Errorf("unknown preflight check %q, must be one of %s or %s", name, strings.Join(Checks, ", "), All)
+		}
+		skipped[name] = true
+	}
+	if skipped[All] {
+		return nil
+	}
+
+	enabled := map[string]bool{
+		CleanWorktree:    config.CleanWorktree,
+		Branch:           len(config.AllowedBranches) > 0,
+		UpToDate:         config.UpToDate,
+		YamlVersions:     config.YamlVersions,
+		VersionIncrement: config.VersionIncrement,
+	}
+
+	var errs []error
+	for _, name := range Checks {
+		if !enabled[name] {
+			continue
+		}
+		if skipped[name] {
+			message.Warnf("Skipping the %s preflight check\n", name)
+			continue
+		}
+
+		var err error
+		switch name {
+		case CleanWorktree:
+			err = checkCleanWorktree()
+		case Branch:
+			err = checkBranch(config.AllowedBranches)
+		case UpToDate:
+			err = checkUpToDate(opts)
+		case YamlVersions:
+			err = checkYamlVersions(flavor)
+		case VersionIncrement:
+			err = checkVersionIncrement(flavor, flavors)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s check failed: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkCleanWorktree() error {
+	changes, err := utils.GetUncommittedChanges()
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		sort.Strings(changes)
+		return fmt.Errorf("the worktree has uncommitted changes: %s", strings.Join(changes, ", "))
+	}
+	return nil
+}
+
+func checkBranch(allowedBranches []string) error {
+	branch, err := utils.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	for _, pattern := range allowedBranches {
+		matched, err := path.Match(pattern, branch)
+		if err != nil {
+			return fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return nil
+		}
+	}
+	return fmt.Errorf("branch %s does not match any of the allowed branches %s", branch, strings.Join(allowedBranches, ", "))
+}
+
+func checkUpToDate(opts Options) error {
+	branch, err := utils.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	remoteURL, _, err := utils.GetRepoInfo()
+	if err != nil {
+		return err
+	}
+
+	remoteCommit, err := utils.GetRemoteBranchCommit(branch, utils.GitAuth(remoteURL, opts.TokenVarName), opts.HTTPConfig)
+	if err != nil {
+		return err
+	}
+
+	head, err := utils.GetRevisionCommit("HEAD")
+	if err != nil {
+		return err
+	}
+
+	if head.Hash.String() != remoteCommit {
+		return fmt.Errorf("HEAD (%s) is not the tip of origin/%s (%s)", utils.ShortSHA(head.Hash.String()), branch, utils.ShortSHA(remoteCommit))
+	}
+	return nil
+}
+
+func checkYamlVersions(flavor types.Flavor) error {
+	mismatches, err := version.VerifyYamls(flavor)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) > 0 {
+		var details []string
+		for _, mismatch := range mismatches {
+			details = append(details, mismatch.String())
+		}
+		return fmt.Errorf("versions do not match the flavor, run update-yaml: %s", strings.Join(details, "; "))
+	}
+	return nil
+}
+
+func checkVersionIncrement(flavor types.Flavor, flavors []types.Flavor) error {
+	latestTag, err := utils.GetLatestFlavorTag(flavor.Name, flavors)
+	if err != nil {
+		return err
+	}
+	if latestTag != nil && utils.CompareVersions(flavor.Version, latestTag.Version, flavor.Name) <= 0 {
+		return fmt.Errorf("version %s is not greater than the latest tag %s", flavor.Version, latestTag.Name)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package preflight
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRepo(t *testing.T) (*git.Repository, *git.Worktree) {
+	originDir := t.TempDir()
+	_, err := git.PlainInit(originDir, true)
+	require.NoError(t, err)
+
+	repo := testutil.InitRepo(t)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}})
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("kind: ZarfPackageConfig\nmetadata:\n  name: test\n  version: 1.0.1-uds.0\n"), 0644))
+	commit(t, repo, worktree, "1.0.0-uds.0-upstream")
+	return repo, worktree
+}
+
+func commit(t *testing.T, repo *git.Repository, worktree *git.Worktree, tag string) {
+	_, err := worktree.Add(".")
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	hash, err := worktree.Commit("commit", &git.CommitOptions{AllowEmptyCommits: true, Author: signature})
+	require.NoError(t, err)
+	if tag != "" {
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	repo, worktree := setupRepo(t)
+	require.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin"}))
+
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.1-uds.0"}
+	flavors := []types.Flavor{flavor}
+	allChecks := types.PreflightConfig{
+		CleanWorktree:    true,
+		AllowedBranches:  []string{"release/*", "master"},
+		UpToDate:         true,
+		YamlVersions:     true,
+		VersionIncrement: true,
+	}
+
+	require.NoError(t, Run(allChecks, flavor, flavors, Options{}))
+
+	// Every failure is reported together
+	require.NoError(t, os.WriteFile("untracked.txt", []byte("dirty"), 0644))
+	commit(t, repo, worktree, "")
+	require.NoError(t, os.WriteFile("dirty.txt", []byte("dirty"), 0644))
+	olderFlavor := types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}
+	err := Run(allChecks, olderFlavor, flavors, Options{})
+	require.ErrorContains(t, err, "clean-worktree check failed: the worktree has uncommitted changes: dirty.txt")
+	require.ErrorContains(t, err, "up-to-date check failed: HEAD")
+	require.ErrorContains(t, err, `yaml-versions check failed: versions do not match the flavor, run update-yaml: zarf.yaml metadata.version is "1.0.1-uds.0", expected "1.0.0-uds.0"`)
+	require.ErrorContains(t, err, "version-increment check failed: version 1.0.0-uds.0 is not greater than the latest tag 1.0.0-uds.0-upstream")
+	require.NotContains(t, err.Error(), "branch check failed")
+
+	require.NoError(t, Run(allChecks, olderFlavor, flavors, Options{SkipChecks: []string{All}}))
+	require.NoError(t, Run(types.PreflightConfig{}, olderFlavor, flavors, Options{}))
+	err = Run(allChecks, olderFlavor, flavors, Options{SkipChecks: []string{CleanWorktree, UpToDate, YamlVersions}})
+	require.ErrorContains(t, err, "version-increment check failed")
+	require.NotContains(t, err.Error(), "clean-worktree")
+
+	err = Run(types.PreflightConfig{AllowedBranches: []string{"main", "release/*"}}, flavor, flavors, Options{})
+	require.EqualError(t, err, "branch check failed: branch master does not match any of the allowed branches main, release/*")
+
+	err = Run(allChecks, flavor, flavors, Options{SkipChecks: []string{"tests"}})
+	require.ErrorContains(t, err, `unknown preflight check "tests"`)
+}
+
+func TestCheckBranchDetached(t *testing.T) {
+	repo, _ := setupRepo(t)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Hash: head.Hash()}))
+
+	t.Setenv("CI_COMMIT_BRANCH", "")
+	t.Setenv("GITHUB_REF_TYPE", "")
+	require.ErrorContains(t, checkBranch([]string{"main"}), "HEAD is detached")
+
+	t.Setenv("GITHUB_REF_TYPE", "branch")
+	t.Setenv("GITHUB_REF_NAME", "release/1.0")
+	require.NoError(t, checkBranch([]string{"main", "release/*"}))
+
+	t.Setenv("CI_COMMIT_BRANCH", "feature")
+	require.ErrorContains(t, checkBranch([]string{"main"}), "branch feature does not match")
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package status
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// Release is an existing flavor tag and whether a forge release was created for it
+type Release struct {
+	Flavor string `json:"flavor"`
+	utils.GitTag
+	Released *bool `json:"released"`
+}
+
+// ListReleases returns the tags of the given flavors, newest first, that were committed after since (when set)
+func ListReleases(releaseConfig types.ReleaseConfig, flavors []types.Flavor, since time.Time, opts Options) ([]Release, error) {
+	releases := []Release{}
+
+	for _, flavor := range flavors {
+		flavorTags, err := utils.GetFlavorTags(flavor.Name, releaseConfig.Flavors)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(flavorTags) - 1; i >= 0; i-- {
+			tag := flavorTags[i]
+			if !since.IsZero() && tag.CommitDate.Before(since) {
+				continue
+			}
+
+			release := Release{Flavor: flavor.Name, GitTag: tag}
+			if opts.Platform != nil {
+				released, err := hasRelease(releaseConfig, tag.Name, opts)
+				if err != nil {
+					message.Warnf("Unable to look up the release for %s: %s\n", tag.Name, err)
+				} else {
+					release.Released = boolPtr(released)
+				}
+			}
+
+			releases = append(releases, release)
+		}
+	}
+
+	return releases, nil
+}
+
+// WriteReleasesTable renders the releases as an aligned table
+func WriteReleasesTable(w io.Writer, releases []Release) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAVOR\tVERSION\tTAG\tDATE\tCOMMIT\tRELEASED")
+
+	for _, release := range releases {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			release.Flavor,
+			release.Version,
+			release.Name,
+			release.CommitDate.Format(time.DateOnly),
+			utils.ShortSHA(release.CommitSHA),
+			formatBool(release.Released, "yes", "no"),
+		)
+	}
+
+	return tw.Flush()
+}
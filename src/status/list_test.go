@@ -0,0 +1,88 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package status
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlatform reports a release for every tag in released, a tag for every tag in tagged and fails for tags in broken
+type fakePlatform struct {
+	released map[string]bool
+	tagged   map[string]bool
+	broken   map[string]bool
+}
+
+func (p fakePlatform) HasTag(tag string, _ string, _ *http.Client) (bool, error) {
+	if p.broken[tag] {
+		return false, errors.New("forge unavailable")
+	}
+	return p.tagged[tag], nil
+}
+
+func (p fakePlatform) HasRelease(tag string, _ string, _ *http.Client) (bool, error) {
+	if p.broken[tag] {
+		return false, errors.New("forge unavailable")
+	}
+	return p.released[tag], nil
+}
+
+func TestListReleases(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	for _, tag := range []string{"1.0.0-uds.0-upstream", "1.0.0-uds.0-registry1", "1.0.0-uds.1-upstream", "1.1.0-uds.0-upstream"} {
+		signature.When = signature.When.AddDate(0, 1, 0)
+		hash, err := worktree.Commit(tag, &git.CommitOptions{AllowEmptyCommits: true, Author: signature})
+		require.NoError(t, err)
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+
+	releaseConfig := types.ReleaseConfig{Flavors: []types.Flavor{{Name: "upstream"}, {Name: "registry1"}}}
+	opts := Options{Platform: fakePlatform{
+		released: map[string]bool{"1.0.0-uds.0-upstream": true, "1.1.0-uds.0-upstream": true},
+		broken:   map[string]bool{"1.0.0-uds.1-upstream": true},
+	}}
+
+	releases, err := ListReleases(releaseConfig, releaseConfig.Flavors, time.Time{}, opts)
+	require.NoError(t, err)
+	require.Len(t, releases, 4)
+	require.Equal(t, "1.1.0-uds.0-upstream", releases[0].Name)
+	require.True(t, *releases[0].Released)
+	require.Nil(t, releases[1].Released)
+	require.Equal(t, "1.0.0-uds.0", releases[2].Version)
+	require.Equal(t, "registry1", releases[3].Flavor)
+	require.False(t, *releases[3].Released)
+
+	// In aggregate mode the flavor tags are released by the release of their version
+	releaseConfig.Aggregate.Enabled = true
+	opts.Platform = fakePlatform{tagged: map[string]bool{"1.0.0-uds.0-registry1": true}}
+	releases, err = ListReleases(releaseConfig, releaseConfig.Flavors, time.Time{}, opts)
+	require.NoError(t, err)
+	require.False(t, *releases[0].Released)
+	require.True(t, *releases[3].Released)
+
+	since := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	releases, err = ListReleases(releaseConfig, releaseConfig.Flavors[:1], since, Options{})
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	require.Nil(t, releases[0].Released)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReleasesTable(&out, releases))
+	require.Regexp(t, `upstream\s+1.1.0-uds.0\s+1.1.0-uds.0-upstream\s+2024-10-01\s+[0-9a-f]{8}\s+unknown`, out.String())
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/defenseunicorns/uds-pk/src/version"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// ReleaseLookup looks up the tags and releases of a forge
+type ReleaseLookup interface {
+	HasRelease(tag string, tokenVarName string, httpClient *http.Client) (bool, error)
+	HasTag(tag string, tokenVarName string, httpClient *http.Client) (bool, error)
+}
+
+// Options controls which remote systems are queried for the status of each flavor
+type Options struct {
+	// Platform is used to look up forge releases, the release column is unknown when it is nil
+	Platform     ReleaseLookup
+	TokenVarName string
+	HTTPClient   *http.Client
+	PlainHTTP    bool
+}
+
+// FlavorStatus is where a single flavor stands in the release process. Checks that could
+// not be performed are left nil and reported as unknown.
+type FlavorStatus struct {
+	Flavor     string             `json:"flavor"`
+	Version    string             `json:"version"`
+	YamlsMatch *bool              `json:"yamlsMatch"`
+	Mismatches []version.Mismatch `json:"mismatches,omitempty"`
+	LatestTag  *utils.GitTag      `json:"latestTag"`
+	Released   *bool              `json:"released"`
+	Published  *bool              `json:"published"`
+}
+
+// GetStatus collects the status of every configured flavor. Failed checks are logged as
+// warnings rather than returned so one unreachable system does not hide the others.
+func GetStatus(ctx context.Context, releaseConfig types.ReleaseConfig, opts Options) []FlavorStatus {
+	statuses := make([]FlavorStatus, 0, len(releaseConfig.Flavors))
+
+	for _, flavor := range releaseConfig.Flavors {
+		flavorStatus := FlavorStatus{
+			Flavor:  flavor.Name,
+			Version: flavor.Version,
+		}
+		tag := oci.FlavorTag(flavor)
+
+		mismatches, err := version.VerifyYamls(flavor)
+		if err != nil {
+			message.Warnf("Unable to verify the yaml versions for %s: %s\n", flavor.Name, err)
+		} else {
+			flavorStatus.YamlsMatch = boolPtr(len(mismatches) == 0)
+			flavorStatus.Mismatches = mismatches
+		}
+
+		flavorStatus.LatestTag, err = utils.GetLatestFlavorTag(flavor.Name, releaseConfig.Flavors)
+		if err != nil {
+			message.Warnf("Unable to find the tags for %s: %s\n", flavor.Name, err)
+		}
+
+		if opts.Platform != nil {
+			released, err := hasRelease(releaseConfig, tag, opts)
+			if err != nil {
+				message.Warnf("Unable to look up the release for %s: %s\n", tag, err)
+			} else {
+				flavorStatus.Released = boolPtr(released)
+			}
+		}
+
+		if flavor.PublishPackageUrl != "" {
+			_, err := oci.VerifyPublished(ctx, flavor, oci.RegistryOptions{HTTPClient: opts.HTTPClient, PlainHTTP: opts.PlainHTTP})
+			if errors.Is(err, oci.ErrNotPublished) {
+				flavorStatus.Published = boolPtr(false)
+			} else if err != nil {
+				message.Warnf("Unable to verify %s is published: %s\n", tag, err)
+			} else {
+				flavorStatus.Published = boolPtr(true)
+			}
+		}
+
+		statuses = append(statuses, flavorStatus)
+	}
+
+	return statuses
+}
+
+// WriteTable renders the statuses as an aligned table
+func WriteTable(w io.Writer, statuses []FlavorStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAVOR\tVERSION\tYAML\tLATEST TAG\tRELEASED\tPUBLISHED")
+
+	for _, flavorStatus := range statuses {
+		latestTag := "-"
+		if flavorStatus.LatestTag != nil {
+			latestTag = flavorStatus.LatestTag.Name
+		}
+
+		yaml := formatBool(flavorStatus.YamlsMatch, "match", "mismatch")
+		if len(flavorStatus.Mismatches) > 0 {
+			fields := make([]string, 0, len(flavorStatus.Mismatches))
+			for _, mismatch := range flavorStatus.Mismatches {
+				fields = append(fields, fmt.Sprintf("%s:%s", mismatch.File, mismatch.Field))
+			}
+			yaml = fmt.Sprintf("%s (%s)", yaml, strings.Join(fields, ", "))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			flavorStatus.Flavor,
+			flavorStatus.Version,
+			yaml,
+			latestTag,
+			formatBool(flavorStatus.Released, "yes", "no"),
+			formatBool(flavorStatus.Published, "yes", "no"),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON renders statuses or releases as indented JSON
+func WriteJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// hasRelease reports whether the tag was released, in aggregate mode the flavor tags are only created by the releaser
+// and released together in the release of their version
+func hasRelease(releaseConfig types.ReleaseConfig, tag string, opts Options) (bool, error) {
+	if releaseConfig.Aggregate.Enabled {
+		return opts.Platform.HasTag(tag, opts.TokenVarName, opts.HTTPClient)
+	}
+	return opts.Platform.HasRelease(tag, opts.TokenVarName, opts.HTTPClient)
+}
+
+func formatBool(value *bool, trueString string, falseString string) string {
+	switch {
+	case value == nil:
+		return "unknown"
+	case *value:
+		return trueString
+	default:
+		return falseString
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/oci"
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/version"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestGetStatus(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com"}
+	hash, err := worktree.Commit("release", &git.CommitOptions{AllowEmptyCommits: true, Author: signature})
+	require.NoError(t, err)
+	_, err = repo.CreateTag("1.0.0-uds.0-upstream", hash, nil)
+	require.NoError(t, err)
+
+	zarfYaml := "kind: ZarfPackageConfig\nmetadata:\n  name: testing-package\n  version: 1.0.0-uds.1\n"
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte(zarfYaml), 0o644))
+
+	// Only the upstream package is published
+	registryOpts := oci.RegistryOptions{PlainHTTP: true}
+	ociRepo, err := oci.NewRepository(registryHost+"/packages/testing-package", registryOpts)
+	require.NoError(t, err)
+	desc, err := oras.PackManifest(context.Background(), ociRepo, oras.PackManifestVersion1_1, "application/vnd.uds.test", oras.PackManifestOptions{})
+	require.NoError(t, err)
+	require.NoError(t, ociRepo.Tag(context.Background(), desc, "1.0.0-uds.1-upstream"))
+
+	releaseConfig := types.ReleaseConfig{Flavors: []types.Flavor{
+		{Name: "upstream", Version: "1.0.0-uds.1", PublishPackageUrl: "oci://" + registryHost + "/packages"},
+		{Name: "registry1", Version: "1.0.0-uds.2", PublishPackageUrl: "oci://" + registryHost + "/packages"},
+		{Name: "unicorn", Version: "1.0.0-uds.1"},
+	}}
+	opts := Options{
+		Platform: fakePlatform{
+			released: map[string]bool{"1.0.0-uds.1-upstream": true},
+			broken:   map[string]bool{"1.0.0-uds.1-unicorn": true},
+		},
+		PlainHTTP: true,
+	}
+
+	statuses := GetStatus(context.Background(), releaseConfig, opts)
+	require.Len(t, statuses, 3)
+
+	upstream := statuses[0]
+	require.Equal(t, "1.0.0-uds.1", upstream.Version)
+	require.True(t, *upstream.YamlsMatch)
+	require.Equal(t, "1.0.0-uds.0-upstream", upstream.LatestTag.Name)
+	require.True(t, *upstream.Released)
+	require.True(t, *upstream.Published)
+
+	// The zarf.yaml is behind, the flavor was never tagged or released and its package is not published
+	registry1 := statuses[1]
+	require.False(t, *registry1.YamlsMatch)
+	require.Equal(t, []version.Mismatch{{File: "zarf.yaml", Field: "metadata.version", Expected: "1.0.0-uds.2", Actual: "1.0.0-uds.1"}}, registry1.Mismatches)
+	require.Nil(t, registry1.LatestTag)
+	require.False(t, *registry1.Released)
+	require.False(t, *registry1.Published)
+
+	// Checks that fail or do not apply are unknown
+	unicorn := statuses[2]
+	require.Nil(t, unicorn.Released)
+	require.Nil(t, unicorn.Published)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteJSON(&out, statuses))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 3)
+	require.Equal(t, "upstream", decoded[0]["flavor"])
+	require.Equal(t, "1.0.0-uds.0-upstream", decoded[0]["latestTag"].(map[string]any)["name"])
+	require.Equal(t, false, decoded[1]["yamlsMatch"])
+	require.Len(t, decoded[1]["mismatches"], 1)
+	require.Nil(t, decoded[1]["latestTag"])
+	require.Nil(t, decoded[2]["released"])
+	require.Nil(t, decoded[2]["published"])
+	require.NotContains(t, decoded[2], "mismatches")
+}
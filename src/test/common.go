@@ -109,6 +109,25 @@ func (e2e *UDSPKE2ETest) CreateUDSBundleYaml(t *testing.T, dir string) {
 	require.NoError(t, err)
 }
 
+func (e2e *UDSPKE2ETest) CreateRemoteUDSBundleYaml(t *testing.T, dir string, repository string) {
+	// Create a uds-bundle.yaml file that pulls the package from a repository
+	var udsBundle uds.UDSBundle
+	udsBundle.Metadata.Name = "testing-remote-bundle"
+	udsBundle.Metadata.Version = "devel"
+	testingPackage := uds.Package{
+		Name:       "testing-package",
+		Repository: repository,
+		Ref:        "devel",
+	}
+	udsBundle.Packages = []uds.Package{testingPackage}
+
+	data, err := goyaml.Marshal(udsBundle)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "uds-bundle.yaml"), data, 0o644)
+	require.NoError(t, err)
+}
+
 func (e2e *UDSPKE2ETest) LoadYaml(path string, destVar interface{}) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
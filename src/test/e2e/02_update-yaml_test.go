@@ -4,6 +4,7 @@
 package test
 
 import (
+	"os"
 	"testing"
 
 	uds "github.com/defenseunicorns/uds-cli/src/types"
@@ -38,3 +39,71 @@ func TestUpdateYamlCommand(t *testing.T) {
 	require.Equal(t, "1.0.0-uds.0", bundle.Metadata.Version)
 	require.Equal(t, "1.0.0-uds.0", bundle.Packages[0].Ref)
 }
+
+func TestUpdateYamlCommandBundleFiles(t *testing.T) {
+	e2e.CreateSandboxDir(t, "bundle", "bundle/remote")
+	defer e2e.CleanupSandboxDir(t)
+
+	e2e.CreateZarfYaml(t, "src/test/sandbox")
+	e2e.CreateUDSBundleYaml(t, "src/test/sandbox/bundle")
+	e2e.CreateRemoteUDSBundleYaml(t, "src/test/sandbox/bundle/remote", "ghcr.io/defenseunicorns/packages/uds/testing-package")
+
+	stdout, stderr, err := e2e.UDSPKDir("src/test/sandbox", "release", "update-yaml", "bundles", "-d", "../")
+	require.NoError(t, err, stdout, stderr)
+
+	// Local packages are referenced by their version
+	var bundle uds.UDSBundle
+	err = e2e.LoadYaml("src/test/sandbox/bundle/uds-bundle.yaml", &bundle)
+	require.NoError(t, err)
+
+	require.Equal(t, "1.0.0-uds.0", bundle.Metadata.Version)
+	require.Equal(t, "1.0.0-uds.0", bundle.Packages[0].Ref)
+
+	// Packages pulled from a repository are referenced by their published tag
+	var remoteBundle uds.UDSBundle
+	err = e2e.LoadYaml("src/test/sandbox/bundle/remote/uds-bundle.yaml", &remoteBundle)
+	require.NoError(t, err)
+
+	require.Equal(t, "1.0.0-uds.0", remoteBundle.Metadata.Version)
+	require.Equal(t, "1.0.0-uds.0-bundles", remoteBundle.Packages[0].Ref)
+}
+
+func TestUpdateYamlCommandHooks(t *testing.T) {
+	e2e.CreateSandboxDir(t, "bundle")
+	defer e2e.CleanupSandboxDir(t)
+
+	e2e.CreateZarfYaml(t, "src/test/sandbox")
+	e2e.CreateUDSBundleYaml(t, "src/test/sandbox/bundle")
+
+	releaserYaml := `flavors:
+  - name: hooks
+    version: 1.0.0-uds.0
+    hooks:
+      preUpdateYaml:
+        - echo "$UDS_PK_PACKAGE $UDS_PK_TAG" > hook.out
+  - name: failing
+    version: 1.0.0-uds.0
+    hooks:
+      preUpdateYaml:
+        - exit 1
+`
+	err := os.WriteFile("src/test/sandbox/releaser.yaml", []byte(releaserYaml), 0o644)
+	require.NoError(t, err)
+
+	stdout, stderr, err := e2e.UDSPKDir("src/test/sandbox", "release", "update-yaml", "hooks")
+	require.NoError(t, err, stdout, stderr)
+
+	hookOutput, err := os.ReadFile("src/test/sandbox/hook.out")
+	require.NoError(t, err)
+	require.Equal(t, "testing-package 1.0.0-uds.0-hooks\n", string(hookOutput))
+
+	// A failing preUpdateYaml hook leaves the yaml files untouched
+	e2e.CreateZarfYaml(t, "src/test/sandbox")
+	stdout, stderr, err = e2e.UDSPKDir("src/test/sandbox", "release", "update-yaml", "failing")
+	require.Error(t, err, stdout, stderr)
+
+	var zarfPackage zarf.ZarfPackage
+	err = e2e.LoadYaml("src/test/sandbox/zarf.yaml", &zarfPackage)
+	require.NoError(t, err)
+	require.Equal(t, "devel", zarfPackage.Metadata.Version)
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const buildZarfYaml = `kind: ZarfPackageConfig
+metadata:
+  name: testing-package
+  version: 1.0.0-uds.0
+components:
+  - name: config
+    required: true
+    only:
+      flavor: base
+    files:
+      - source: config.txt
+        target: /tmp/config.txt
+`
+
+func TestBuildCommand(t *testing.T) {
+	e2e.CreateSandboxDir(t)
+	defer e2e.CleanupSandboxDir(t)
+
+	err := os.WriteFile("src/test/sandbox/zarf.yaml", []byte(buildZarfYaml), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile("src/test/sandbox/config.txt", []byte("testing"), 0o644)
+	require.NoError(t, err)
+
+	stdout, stderr, err := e2e.UDSPKDir("src/test/sandbox", "release", "build", "base", "-d", "../", "-a", "amd64", "--skip-sbom")
+	require.NoError(t, err, stdout, stderr)
+
+	require.FileExists(t, "src/test/sandbox/zarf-package-testing-package-amd64-1.0.0-uds.0.tar.zst")
+
+	// The zarf.yaml version must match the flavor version
+	stdout, stderr, err = e2e.UDSPKDir("src/test/sandbox", "release", "build", "patch", "-d", "../", "-a", "amd64", "--skip-sbom")
+	require.Error(t, err, stdout, stderr)
+	require.Contains(t, stderr, "run update-yaml first")
+}
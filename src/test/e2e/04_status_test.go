@@ -0,0 +1,40 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCommand(t *testing.T) {
+	stdout, stderr, err := e2e.UDSPK("release", "status", "-d", "src/test", "--platform", "none", "-o", "json")
+	require.NoError(t, err, stdout, stderr)
+
+	var statuses []status.FlavorStatus
+	require.NoError(t, json.Unmarshal([]byte(stdout), &statuses))
+	require.Len(t, statuses, 7)
+
+	require.Equal(t, "base", statuses[0].Flavor)
+	require.Equal(t, "1.0.0-uds.0", statuses[0].Version)
+	require.Nil(t, statuses[0].LatestTag)
+	require.Nil(t, statuses[0].Released)
+	require.Nil(t, statuses[0].Published)
+
+	require.Equal(t, "dummy", statuses[5].Flavor)
+	require.NotNil(t, statuses[5].LatestTag)
+	require.Equal(t, "testing-dummy", statuses[5].LatestTag.Name)
+
+	stdout, stderr, err = e2e.UDSPK("release", "status", "-d", "src/test", "--platform", "none")
+	require.NoError(t, err, stdout, stderr)
+
+	require.Contains(t, stdout, "FLAVOR")
+	require.Regexp(t, `dummy\s+testing\s+unknown\s+testing-dummy\s+unknown\s+unknown`, stdout)
+
+	_, _, err = e2e.UDSPK("release", "status", "-d", "src/test", "-o", "yaml")
+	require.Error(t, err)
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommand(t *testing.T) {
+	stdout, stderr, err := e2e.UDSPK("release", "list", "dummy", "-d", "src/test", "--platform", "none", "-o", "json")
+	require.NoError(t, err, stdout, stderr)
+
+	var releases []status.Release
+	require.NoError(t, json.Unmarshal([]byte(stdout), &releases))
+	require.Len(t, releases, 1)
+	require.Equal(t, "testing-dummy", releases[0].Name)
+	require.Equal(t, "testing", releases[0].Version)
+	require.Len(t, releases[0].CommitSHA, 40)
+
+	stdout, stderr, err = e2e.UDSPK("release", "list", "base", "-d", "src/test", "--platform", "none")
+	require.NoError(t, err, stdout, stderr)
+	require.Equal(t, "FLAVOR  VERSION  TAG  DATE  COMMIT  RELEASED\n", stdout)
+
+	_, _, err = e2e.UDSPK("release", "list", "dummy", "-d", "src/test", "--since", "last-quarter")
+	require.Error(t, err)
+}
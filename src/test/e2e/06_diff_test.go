@@ -0,0 +1,20 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCommand(t *testing.T) {
+	stdout, stderr, err := e2e.UDSPK("release", "diff", "base", "-d", "src/test")
+	require.Error(t, err, stdout, stderr)
+	require.Contains(t, stderr, "flavor base has no previous release to compare against")
+
+	stdout, stderr, err = e2e.UDSPK("release", "diff", "base", "HEAD", "-d", "src/test", "-o", "yaml")
+	require.Error(t, err, stdout, stderr)
+	require.Contains(t, stderr, "unsupported output format")
+}
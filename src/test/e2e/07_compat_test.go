@@ -0,0 +1,16 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatCommand(t *testing.T) {
+	stdout, stderr, err := e2e.UDSPK("release", "compat", "base", "-d", "src/test", "--fail-on-breaking")
+	require.Error(t, err, stdout, stderr)
+	require.Contains(t, stderr, "flavor base has no previous release to compare against")
+}
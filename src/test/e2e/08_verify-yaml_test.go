@@ -0,0 +1,40 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestVerifyYamlCommand(t *testing.T) {
+	e2e.CreateSandboxDir(t, "bundle")
+	defer e2e.CleanupSandboxDir(t)
+
+	e2e.CreateZarfYaml(t, "src/test/sandbox")
+	e2e.CreateUDSBundleYaml(t, "src/test/sandbox/bundle")
+
+	stdout, stderr, err := e2e.UDSPKDir("src/test/sandbox", "release", "verify-yaml", "base", "-d", "../")
+	require.Error(t, err, stdout, stderr)
+	require.Contains(t, stdout, "--- zarf.yaml\n+++ zarf.yaml\n-metadata.version: devel\n+metadata.version: 1.0.0-uds.0\n")
+	require.Contains(t, stdout, "--- bundle/uds-bundle.yaml\n+++ bundle/uds-bundle.yaml\n-metadata.version: devel\n+metadata.version: 1.0.0-uds.0\n-packages[0].ref: devel\n+packages[0].ref: 1.0.0-uds.0\n")
+	require.Contains(t, stderr, "version fields do not match 1.0.0-uds.0-base")
+
+	// Nothing is written when verifying
+	stdout, stderr, err = e2e.UDSPKDir("src/test/sandbox", "release", "update-yaml", "base", "--check", "-d", "../")
+	require.Error(t, err, stdout, stderr)
+
+	var zarfPackage zarf.ZarfPackage
+	require.NoError(t, e2e.LoadYaml("src/test/sandbox/zarf.yaml", &zarfPackage))
+	require.Equal(t, "devel", zarfPackage.Metadata.Version)
+
+	stdout, stderr, err = e2e.UDSPKDir("src/test/sandbox", "release", "update-yaml", "base", "-d", "../")
+	require.NoError(t, err, stdout, stderr)
+
+	stdout, stderr, err = e2e.UDSPKDir("src/test/sandbox", "release", "verify-yaml", "base", "-d", "../")
+	require.NoError(t, err, stdout, stderr)
+	require.Contains(t, stdout, "Versions match 1.0.0-uds.0-base")
+}
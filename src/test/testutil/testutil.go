@@ -0,0 +1,38 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+// Package testutil contains helpers shared by the unit tests
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Chdir changes the working directory to a new temporary directory for the rest of the test and returns it. The
+// original working directory is restored when the test finishes.
+func Chdir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(cwd))
+	})
+	return dir
+}
+
+// InitRepo changes the working directory to a new temporary directory like Chdir and initializes a git repository in it
+func InitRepo(t *testing.T) *git.Repository {
+	t.Helper()
+
+	Chdir(t)
+	repo, err := git.PlainInit(".", false)
+	require.NoError(t, err)
+	return repo
+}
@@ -4,13 +4,144 @@
 package types
 
 type Flavor struct {
-	Name              string `yaml:"name"`
-	Version           string `yaml:"version"`
-	PublishBundle     bool   `yaml:"publishBundle,omitempty,default=false"`
-	PublishPackageUrl string `yaml:"publishPackageUrl"`
-	PublishBundleUrl  string `yaml:"publishBundleUrl,omitempty"`
+	Name              string   `yaml:"name"`
+	Version           string   `yaml:"version"`
+	PublishBundle     bool     `yaml:"publishBundle,omitempty,default=false"`
+	PublishPackageUrl string   `yaml:"publishPackageUrl"`
+	PublishBundleUrl  string   `yaml:"publishBundleUrl,omitempty"`
+	BundleFiles       []string `yaml:"bundleFiles,omitempty"`
+	// BundleRef is a Go template for the ref of the package in bundles that pull it from a repository, with the
+	// Flavor, Version and Tag fields. Defaults to the published <version>-<flavor> tag.
+	BundleRef string `yaml:"bundleRef,omitempty"`
+	Hooks     Hooks  `yaml:"hooks,omitempty"`
 }
 
 type ReleaseConfig struct {
-	Flavors []Flavor `yaml:"flavors"`
+	Flavors       []Flavor             `yaml:"flavors"`
+	HTTP          HTTPConfig           `yaml:"http,omitempty"`
+	Notifications []NotificationConfig `yaml:"notifications,omitempty"`
+	Hooks         Hooks                `yaml:"hooks,omitempty"`
+	Preflight     PreflightConfig      `yaml:"preflight,omitempty"`
+	Changelog     ChangelogConfig      `yaml:"changelog,omitempty"`
+	Notes         NotesConfig          `yaml:"notes,omitempty"`
+	Issues        IssuesConfig         `yaml:"issues,omitempty"`
+	Aggregate     AggregateConfig      `yaml:"aggregate,omitempty"`
+	Snapshot      SnapshotConfig       `yaml:"snapshot,omitempty"`
+	Prune         PruneConfig          `yaml:"prune,omitempty"`
+}
+
+// PruneConfig is the policy uds-pk release prune applies to the release candidates and snapshots of each flavor, final
+// versions and the flavor's current version are never deleted
+type PruneConfig struct {
+	// KeepLast is how many of the newest prereleases of a flavor are always kept
+	KeepLast int `yaml:"keepLast,omitempty"`
+	// OlderThanDays only deletes the prereleases older than this many days, going by the date of the tagged commit or
+	// of the snapshot. When unset every prerelease beyond the KeepLast newest is deleted.
+	OlderThanDays int `yaml:"olderThanDays,omitempty"`
+}
+
+// SnapshotConfig configures the snapshots released with --snapshot
+type SnapshotConfig struct {
+	// RetentionDays is how many days of snapshots are kept in the registry, older <version>-<flavor>-dev.<date>.<shortsha>
+	// tags of the flavor are deleted when a new snapshot is released. Snapshots are kept forever when unset.
+	RetentionDays int `yaml:"retentionDays,omitempty"`
+}
+
+// AggregateConfig enables one release per version summarizing every flavor instead of a release per flavor
+type AggregateConfig struct {
+	// Enabled only creates the <version>-<flavor> tag for each flavor and creates or updates the <version> release
+	// with the tags, OCI references, status and notes of every flavor with that version
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// IssuesConfig configures what is done with the issues referenced with a closing keyword (such as Fixes #123) and the
+// pull requests merged since the previous release once a release is created
+type IssuesConfig struct {
+	// Comment posts a comment linking the release on each referenced issue and merged pull request
+	Comment bool `yaml:"comment,omitempty"`
+	// Close closes the referenced issues that are still open
+	Close bool `yaml:"close,omitempty"`
+	// CloseMilestone closes the open milestone titled with the version or <version>-<flavor>
+	CloseMilestone bool `yaml:"closeMilestone,omitempty"`
+}
+
+// NotesConfig configures the changes listed in the notes of a release
+type NotesConfig struct {
+	// Source of the changes, either commits to group the commits since the previous release by their conventional
+	// commit type or pullRequests to group the pull (or merge) requests merged since the previous release by their
+	// labels. When unset only the upstream changes are included.
+	Source string `yaml:"source,omitempty"`
+	// Categories group pull requests by label in the order they are listed, defaulting to breaking changes,
+	// features, bug fixes and dependencies. Pull requests without a matching label are listed under Other Changes.
+	Categories []NotesCategory `yaml:"categories,omitempty"`
+	// ExcludeLabels leave pull requests out of the notes, defaulting to skip-changelog
+	ExcludeLabels []string `yaml:"excludeLabels,omitempty"`
+	// Directory holds hand-written notes for a version as <version>-<flavor>.md or <version>.md, such as
+	// docs/release-notes
+	Directory string `yaml:"directory,omitempty"`
+	// Placement of the hand-written notes, header (the default) to put them before the generated notes or footer
+	// to put them after
+	Placement string `yaml:"placement,omitempty"`
+}
+
+// NotesCategory is a section of the release notes listing the pull requests with any of its labels
+type NotesCategory struct {
+	Title  string   `yaml:"title"`
+	Labels []string `yaml:"labels"`
+}
+
+// ChangelogConfig configures the changelog written by update-yaml --changelog, release changelog and release pr
+type ChangelogConfig struct {
+	// Path of the changelog, a Go template with the Flavor field such as CHANGELOG-{{ .Flavor }}.md for one file per
+	// flavor. Defaults to CHANGELOG.md
+	Path string `yaml:"path,omitempty"`
+}
+
+// PreflightConfig enables the checks run before a release is tagged, any of which can be skipped with --skip-check
+type PreflightConfig struct {
+	// CleanWorktree requires no uncommitted or untracked changes
+	CleanWorktree bool `yaml:"cleanWorktree,omitempty"`
+	// AllowedBranches are the branch patterns (such as main or release/*) HEAD must be on, any branch if empty
+	AllowedBranches []string `yaml:"allowedBranches,omitempty"`
+	// UpToDate requires HEAD to be the tip of its branch on the origin remote
+	UpToDate bool `yaml:"upToDate,omitempty"`
+	// YamlVersions requires the zarf.yaml and bundle versions to match the flavor version
+	YamlVersions bool `yaml:"yamlVersions,omitempty"`
+	// VersionIncrement requires the flavor version to be greater than the latest existing tag of the flavor
+	VersionIncrement bool `yaml:"versionIncrement,omitempty"`
+}
+
+// Hooks are shell commands run around release steps, global hooks run before the hooks of a flavor
+type Hooks struct {
+	PreRelease    []string `yaml:"preRelease,omitempty"`
+	PostRelease   []string `yaml:"postRelease,omitempty"`
+	PreUpdateYaml []string `yaml:"preUpdateYaml,omitempty"`
+}
+
+// HTTPConfig holds the TLS and proxy settings used by the HTTP client shared across platforms
+type HTTPConfig struct {
+	CAFile                string       `yaml:"caFile,omitempty"`
+	InsecureSkipTLSVerify bool         `yaml:"insecureSkipTLSVerify,omitempty"`
+	Proxy                 string       `yaml:"proxy,omitempty"`
+	NoProxy               string       `yaml:"noProxy,omitempty"`
+	Hosts                 []HostConfig `yaml:"hosts,omitempty"`
+}
+
+// HostConfig overrides the HTTPConfig settings for a single host (optionally including a port)
+type HostConfig struct {
+	Host                  string `yaml:"host"`
+	CAFile                string `yaml:"caFile,omitempty"`
+	InsecureSkipTLSVerify bool   `yaml:"insecureSkipTLSVerify,omitempty"`
+	Proxy                 string `yaml:"proxy,omitempty"`
+}
+
+// NotificationConfig is a webhook that is sent a message when a release is created
+type NotificationConfig struct {
+	// Type is one of slack, mattermost, teams or webhook
+	Type string `yaml:"type"`
+	// URL of the incoming webhook, URLVarName can be used instead to read it from an environment variable
+	URL        string `yaml:"url,omitempty"`
+	URLVarName string `yaml:"urlVarName,omitempty"`
+	// Template is a Go template for the message text, overriding the default message
+	Template string `yaml:"template,omitempty"`
 }
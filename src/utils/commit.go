@@ -0,0 +1,188 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// CommitFiles stages only the given paths and commits them, signing the commit with the key in the signingKeyVarName
+// environment variable when it is set. The author is read from GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL, falling back to
+// the user in the git config. It refuses to commit when other files are already staged, as they would be committed too.
+func CommitFiles(paths []string, commitMessage string, signingKeyVarName string) (string, error) {
+	var signKey *openpgp.Entity
+	if signingKeyVarName != "" {
+		var err error
+		signKey, err = LoadSigningKey(signingKeyVarName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	repo, err := OpenRepo()
+	if err != nil {
+		return "", err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return "", err
+	}
+	var staged []string
+	for path, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified && fileStatus.Staging != git.Untracked && !slices.Contains(paths, path) {
+			staged = append(staged, path)
+		}
+	}
+	if len(staged) > 0 {
+		sort.Strings(staged)
+		return "", fmt.Errorf("refusing to commit with other staged changes to %s, unstage them first", strings.Join(staged, ", "))
+	}
+
+	for _, path := range paths {
+		if _, err := worktree.Add(path); err != nil {
+			return "", fmt.Errorf("unable to stage %s: %w", path, err)
+		}
+	}
+
+	opts := &git.CommitOptions{SignKey: signKey}
+	if name, email := os.Getenv("GIT_AUTHOR_NAME"), os.Getenv("GIT_AUTHOR_EMAIL"); name != "" && email != "" {
+		opts.Author = &object.Signature{Name: name, Email: email, When: time.Now()}
+	}
+
+	hash, err := worktree.Commit(commitMessage, opts)
+	if err != nil {
+		return "", fmt.Errorf("unable to commit, set GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL if no git user is configured: %w", err)
+	}
+	return hash.String(), nil
+}
+
+// CheckoutNewBranch points the branch at HEAD and checks it out, keeping untracked files. The returned function checks
+// out the original branch (or commit when HEAD was detached) again, discarding any uncommitted changes.
+func CheckoutNewBranch(branch string) (func() error, error) {
+	repo, err := OpenRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	for path, fileStatus := range status {
+		if fileStatus.Worktree != git.Untracked && (fileStatus.Worktree != git.Unmodified || fileStatus.Staging != git.Unmodified) {
+			return nil, fmt.Errorf("the worktree has uncommitted changes to %s", path)
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	branchRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), head.Hash())
+	if err := repo.Storer.SetReference(branchRef); err != nil {
+		return nil, err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: branchRef.Name(), Keep: true}); err != nil {
+		return nil, err
+	}
+
+	restore := func() error {
+		opts := &git.CheckoutOptions{Force: true}
+		if head.Name().IsBranch() {
+			opts.Branch = head.Name()
+		} else {
+			opts.Hash = head.Hash()
+		}
+		return worktree.Checkout(opts)
+	}
+	return restore, nil
+}
+
+// PushCommit pushes the commit to the branch on the origin remote with the TLS and proxy settings of the HTTP config.
+// A detached HEAD can be pushed as the commit is pushed by its hash rather than by a local branch.
+func PushCommit(commit string, branch string, force bool, auth transport.AuthMethod, httpConfig types.HTTPConfig) error {
+	repo, err := OpenRepo()
+	if err != nil {
+		return err
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return err
+	}
+	transportOpts, err := newGitTransportOptions(httpConfig, remote.Config().URLs[0])
+	if err != nil {
+		return err
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", commit, plumbing.NewBranchReferenceName(branch)))
+	if force {
+		refSpec = "+" + refSpec
+	}
+
+	err = repo.Push(&git.PushOptions{
+		RemoteName:      "origin",
+		RefSpecs:        []config.RefSpec{refSpec},
+		Auth:            auth,
+		CABundle:        transportOpts.caBundle,
+		InsecureSkipTLS: transportOpts.insecureSkipTLS,
+		ProxyOptions:    transportOpts.proxy,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+// LoadSigningKey reads an ASCII armored OpenPGP private key from the environment variable, decrypting it with the
+// passphrase in <varName>_PASSPHRASE when the key is encrypted
+func LoadSigningKey(varName string) (*openpgp.Entity, error) {
+	armoredKey := os.Getenv(varName)
+	if armoredKey == "" {
+		return nil, fmt.Errorf("%s is unset or empty", varName)
+	}
+
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the signing key from %s: %w", varName, err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, fmt.Errorf("%s does not contain a private key", varName)
+	}
+
+	key := entities[0]
+	if key.PrivateKey.Encrypted {
+		passphrase := []byte(os.Getenv(varName + "_PASSPHRASE"))
+		if err := key.DecryptPrivateKeys(passphrase); err != nil {
+			return nil, fmt.Errorf("unable to decrypt the signing key with %s_PASSPHRASE: %w", varName, err)
+		}
+	}
+	return key, nil
+}
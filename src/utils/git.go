@@ -54,3 +54,11 @@ func GetRepoInfo() (remoteURL string, defaultBranch string, err error) {
 	defaultBranch = ref.Name().Short()
 	return remoteURL, defaultBranch, nil
 }
+
+// ShortSHA abbreviates a commit SHA to its first 8 characters for display
+func ShortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// MergeHTTPConfig returns the base config with any non-empty values from overrides applied on top
+func MergeHTTPConfig(base, overrides types.HTTPConfig) types.HTTPConfig {
+	merged := base
+	if overrides.CAFile != "" {
+		merged.CAFile = overrides.CAFile
+	}
+	if overrides.InsecureSkipTLSVerify {
+		merged.InsecureSkipTLSVerify = true
+	}
+	if overrides.Proxy != "" {
+		merged.Proxy = overrides.Proxy
+	}
+	if overrides.NoProxy != "" {
+		merged.NoProxy = overrides.NoProxy
+	}
+	merged.Hosts = append(merged.Hosts, overrides.Hosts...)
+	return merged
+}
+
+// NewHTTPClient creates an HTTP client that applies the CA bundle, TLS verification and proxy settings
+// from the given config, using the per host settings for any request whose host matches one of config.Hosts
+func NewHTTPClient(config types.HTTPConfig) (*http.Client, error) {
+	defaultTransport, err := newTransport(config.CAFile, config.InsecureSkipTLSVerify, config.Proxy, config.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &hostTransport{
+		defaultTransport: defaultTransport,
+		hostTransports:   map[string]http.RoundTripper{},
+	}
+
+	for _, host := range config.Hosts {
+		if host.Host == "" {
+			return nil, fmt.Errorf("host settings must specify a host")
+		}
+
+		caFile := host.CAFile
+		if caFile == "" {
+			caFile = config.CAFile
+		}
+		proxy := host.Proxy
+		if proxy == "" {
+			proxy = config.Proxy
+		}
+
+		hostTransport, err := newTransport(caFile, host.InsecureSkipTLSVerify || config.InsecureSkipTLSVerify, proxy, config.NoProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings for host %s: %w", host.Host, err)
+		}
+		transport.hostTransports[strings.ToLower(host.Host)] = hostTransport
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// hostSettings returns the CA file, TLS verification and proxy settings of the config for the host of the URL, using
+// the same host matching as NewHTTPClient
+func hostSettings(config types.HTTPConfig, u *url.URL) (caFile string, insecureSkipTLSVerify bool, proxy string) {
+	caFile, insecureSkipTLSVerify, proxy = config.CAFile, config.InsecureSkipTLSVerify, config.Proxy
+
+	var match *types.HostConfig
+	for i, host := range config.Hosts {
+		if strings.EqualFold(host.Host, u.Host) {
+			match = &config.Hosts[i]
+			break
+		}
+		if match == nil && strings.EqualFold(host.Host, u.Hostname()) {
+			match = &config.Hosts[i]
+		}
+	}
+
+	if match != nil {
+		if match.CAFile != "" {
+			caFile = match.CAFile
+		}
+		if match.Proxy != "" {
+			proxy = match.Proxy
+		}
+		insecureSkipTLSVerify = insecureSkipTLSVerify || match.InsecureSkipTLSVerify
+	}
+	return caFile, insecureSkipTLSVerify, proxy
+}
+
+// hostTransport routes each request to the transport configured for its host
+type hostTransport struct {
+	defaultTransport http.RoundTripper
+	hostTransports   map[string]http.RoundTripper
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transportFor(req.URL).RoundTrip(req)
+}
+
+// transportFor returns the transport for the host of the URL, preferring an exact host:port match before falling back
+// to the bare hostname
+func (t *hostTransport) transportFor(u *url.URL) http.RoundTripper {
+	if transport, ok := t.hostTransports[strings.ToLower(u.Host)]; ok {
+		return transport
+	}
+	if transport, ok := t.hostTransports[strings.ToLower(u.Hostname())]; ok {
+		return transport
+	}
+	return t.defaultTransport
+}
+
+// HostTransport returns the transport a client created by NewHTTPClient uses for requests to the host, for libraries
+// that create their own transport and can only be given its TLS and proxy settings
+func HostTransport(client *http.Client, host string) (*http.Transport, bool) {
+	roundTripper := client.Transport
+	if hostTransport, ok := roundTripper.(*hostTransport); ok {
+		roundTripper = hostTransport.transportFor(&url.URL{Host: host})
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	return transport, ok
+}
+
+func newTransport(caFile string, insecureSkipTLSVerify bool, proxy string, noProxy string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipTLSVerify, //nolint:gosec // explicitly requested by the user
+	}
+
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if proxy != "" {
+		if _, err := url.Parse(proxy); err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s: %w", proxy, err)
+		}
+		proxyConfig := httpproxy.Config{
+			HTTPProxy:  proxy,
+			HTTPSProxy: proxy,
+			NoProxy:    noProxy,
+		}
+		proxyFunc := proxyConfig.ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	return transport, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA file: %w", err)
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, data, 0o600))
+	return caFile
+}
+
+func TestNewHTTPClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	caFile := writeServerCA(t, server)
+
+	tests := []struct {
+		name        string
+		config      types.HTTPConfig
+		expectError bool
+	}{
+		{
+			name:        "DefaultRoots",
+			config:      types.HTTPConfig{},
+			expectError: true,
+		},
+		{
+			name:        "CAFile",
+			config:      types.HTTPConfig{CAFile: caFile},
+			expectError: false,
+		},
+		{
+			name:        "InsecureSkipTLSVerify",
+			config:      types.HTTPConfig{InsecureSkipTLSVerify: true},
+			expectError: false,
+		},
+		{
+			name:        "HostCAFile",
+			config:      types.HTTPConfig{Hosts: []types.HostConfig{{Host: serverURL.Hostname(), CAFile: caFile}}},
+			expectError: false,
+		},
+		{
+			name:        "HostWithPortCAFile",
+			config:      types.HTTPConfig{Hosts: []types.HostConfig{{Host: serverURL.Host, CAFile: caFile}}},
+			expectError: false,
+		},
+		{
+			name:        "OtherHostCAFile",
+			config:      types.HTTPConfig{Hosts: []types.HostConfig{{Host: "gitlab.example.com", CAFile: caFile}}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewHTTPClient(tt.config)
+			require.NoError(t, err)
+
+			resp, err := client.Get(server.URL)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				defer resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(types.HTTPConfig{
+		Hosts: []types.HostConfig{{Host: "gitlab.example.com", Proxy: proxy.URL}},
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://gitlab.example.com/api/v4")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "gitlab.example.com", proxiedHost)
+}
+
+func TestNewHTTPClientInvalidConfig(t *testing.T) {
+	_, err := NewHTTPClient(types.HTTPConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+
+	_, err = NewHTTPClient(types.HTTPConfig{Hosts: []types.HostConfig{{CAFile: "ca.pem"}}})
+	assert.Error(t, err)
+}
+
+func TestMergeHTTPConfig(t *testing.T) {
+	base := types.HTTPConfig{
+		CAFile: "config-ca.pem",
+		Proxy:  "http://proxy.example.com:3128",
+		Hosts:  []types.HostConfig{{Host: "gitlab.example.com", InsecureSkipTLSVerify: true}},
+	}
+	overrides := types.HTTPConfig{
+		CAFile:                "flag-ca.pem",
+		InsecureSkipTLSVerify: true,
+	}
+
+	merged := MergeHTTPConfig(base, overrides)
+	assert.Equal(t, "flag-ca.pem", merged.CAFile)
+	assert.True(t, merged.InsecureSkipTLSVerify)
+	assert.Equal(t, "http://proxy.example.com:3128", merged.Proxy)
+	assert.Len(t, merged.Hosts, 1)
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrDetachedHead is returned when the branch of HEAD can not be determined
+var ErrDetachedHead = errors.New("HEAD is detached and no CI branch variable is set")
+
+// GetCurrentBranch returns the branch HEAD is on. CI systems check out a detached HEAD, in which case the branch is
+// read from CI_COMMIT_BRANCH on GitLab or GITHUB_REF_NAME on GitHub.
+func GetCurrentBranch() (string, error) {
+	repo, err := OpenRepo()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if head.Name().IsBranch() {
+		return head.Name().Short(), nil
+	}
+
+	if branch := os.Getenv("CI_COMMIT_BRANCH"); branch != "" {
+		return branch, nil
+	}
+	if os.Getenv("GITHUB_REF_TYPE") == "branch" && os.Getenv("GITHUB_REF_NAME") != "" {
+		return os.Getenv("GITHUB_REF_NAME"), nil
+	}
+	return "", ErrDetachedHead
+}
+
+// GetUncommittedChanges returns the paths of the modified, staged and untracked files in the worktree
+func GetUncommittedChanges() ([]string, error) {
+	repo, err := OpenRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for path, fileStatus := range status {
+		if fileStatus.Worktree != git.Unmodified || fileStatus.Staging != git.Unmodified {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// GetRemoteBranchCommit returns the commit at the tip of the branch on the origin remote, listing the remote with
+// the HTTP config and auth so the result is current rather than as of the last fetch
+func GetRemoteBranchCommit(branch string, auth transport.AuthMethod, httpConfig types.HTTPConfig) (string, error) {
+	repo, err := OpenRepo()
+	if err != nil {
+		return "", err
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", err
+	}
+
+	transportOpts, err := newGitTransportOptions(httpConfig, remote.Config().URLs[0])
+	if err != nil {
+		return "", err
+	}
+
+	refs, err := remote.List(&git.ListOptions{
+		Auth:            auth,
+		CABundle:        transportOpts.caBundle,
+		InsecureSkipTLS: transportOpts.insecureSkipTLS,
+		ProxyOptions:    transportOpts.proxy,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	for _, ref := range refs {
+		if ref.Name() == branchRef {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("branch %s does not exist on origin", branch)
+}
+
+// gitTransportOptions are the TLS and proxy settings of an HTTP config for a single go-git list or push, which go-git
+// applies to that call only rather than replacing its process wide transports
+type gitTransportOptions struct {
+	caBundle        []byte
+	insecureSkipTLS bool
+	proxy           transport.ProxyOptions
+}
+
+// newGitTransportOptions resolves the HTTP config for the host of the remote URL, returning no options for remotes
+// that do not use HTTP(S)
+func newGitTransportOptions(config types.HTTPConfig, remoteURL string) (gitTransportOptions, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return gitTransportOptions{}, nil
+	}
+
+	caFile, insecureSkipTLSVerify, proxy := hostSettings(config, u)
+	opts := gitTransportOptions{insecureSkipTLS: insecureSkipTLSVerify}
+
+	if caFile != "" {
+		opts.caBundle, err = os.ReadFile(caFile)
+		if err != nil {
+			return gitTransportOptions{}, fmt.Errorf("unable to read CA file: %w", err)
+		}
+	}
+
+	if proxy != "" {
+		proxyConfig := httpproxy.Config{HTTPProxy: proxy, HTTPSProxy: proxy, NoProxy: config.NoProxy}
+		proxyURL, err := proxyConfig.ProxyFunc()(u)
+		if err != nil {
+			return gitTransportOptions{}, fmt.Errorf("invalid proxy URL %s: %w", proxy, err)
+		}
+		// A nil proxy URL means the host matched noProxy
+		if proxyURL != nil {
+			opts.proxy = transport.ProxyOptions{URL: proxyURL.String()}
+		}
+	}
+
+	return opts, nil
+}
+
+// GitAuth returns the credentials for the origin remote using the platform token, or nil when the token is not set
+// or the remote does not use HTTP(S)
+func GitAuth(remoteURL string, tokenVarName string) transport.AuthMethod {
+	token := os.Getenv(tokenVarName)
+	if token == "" || !strings.HasPrefix(remoteURL, "http") {
+		return nil
+	}
+
+	// GitHub accepts any username with a token, GitLab requires a specific one for job and OAuth tokens
+	username := "oauth2"
+	switch {
+	case strings.Contains(remoteURL, "github"):
+		username = "x-access-token"
+	case token == os.Getenv("CI_JOB_TOKEN"):
+		username = "gitlab-ci-token"
+	}
+	return &githttp.BasicAuth{Username: username, Password: token}
+}
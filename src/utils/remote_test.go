@@ -0,0 +1,64 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitAuth(t *testing.T) {
+	t.Setenv("TEST_TOKEN", "secret")
+	t.Setenv("CI_JOB_TOKEN", "job")
+
+	require.Equal(t, &githttp.BasicAuth{Username: "x-access-token", Password: "secret"}, GitAuth("https://github.com/defenseunicorns/uds-pk.git", "TEST_TOKEN"))
+	require.Equal(t, &githttp.BasicAuth{Username: "oauth2", Password: "secret"}, GitAuth("https://gitlab.com/defenseunicorns/uds-pk.git", "TEST_TOKEN"))
+	require.Equal(t, &githttp.BasicAuth{Username: "gitlab-ci-token", Password: "job"}, GitAuth("https://gitlab.com/defenseunicorns/uds-pk.git", "CI_JOB_TOKEN"))
+	require.Nil(t, GitAuth("git@github.com:defenseunicorns/uds-pk.git", "TEST_TOKEN"))
+	require.Nil(t, GitAuth("https://github.com/defenseunicorns/uds-pk.git", "UNSET_TOKEN"))
+}
+
+func TestNewGitTransportOptions(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("ca bundle"), 0o600))
+
+	config := types.HTTPConfig{
+		Proxy:   "http://proxy.example.com:3128",
+		NoProxy: "internal.example.com",
+		Hosts: []types.HostConfig{
+			{Host: "gitlab.example.com", CAFile: caFile, InsecureSkipTLSVerify: true},
+			{Host: "gitlab.example.com:8443", Proxy: "http://other-proxy.example.com:3128"},
+		},
+	}
+
+	opts, err := newGitTransportOptions(config, "https://github.com/defenseunicorns/uds-pk.git")
+	require.NoError(t, err)
+	require.Equal(t, gitTransportOptions{proxy: transport.ProxyOptions{URL: "http://proxy.example.com:3128"}}, opts)
+
+	opts, err = newGitTransportOptions(config, "https://gitlab.example.com/defenseunicorns/uds-pk.git")
+	require.NoError(t, err)
+	require.Equal(t, gitTransportOptions{caBundle: []byte("ca bundle"), insecureSkipTLS: true, proxy: transport.ProxyOptions{URL: "http://proxy.example.com:3128"}}, opts)
+
+	// An exact host:port match is preferred over the bare hostname
+	opts, err = newGitTransportOptions(config, "https://gitlab.example.com:8443/defenseunicorns/uds-pk.git")
+	require.NoError(t, err)
+	require.Equal(t, gitTransportOptions{proxy: transport.ProxyOptions{URL: "http://other-proxy.example.com:3128"}}, opts)
+
+	opts, err = newGitTransportOptions(config, "https://internal.example.com/defenseunicorns/uds-pk.git")
+	require.NoError(t, err)
+	require.Equal(t, gitTransportOptions{}, opts)
+
+	opts, err = newGitTransportOptions(config, "git@github.com:defenseunicorns/uds-pk.git")
+	require.NoError(t, err)
+	require.Equal(t, gitTransportOptions{}, opts)
+
+	_, err = newGitTransportOptions(types.HTTPConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}, "https://github.com/defenseunicorns/uds-pk.git")
+	require.ErrorContains(t, err, "unable to read CA file")
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	goyaml "github.com/goccy/go-yaml"
+)
+
+// ErrFileNotFound is returned when a file does not exist at a revision
+var ErrFileNotFound = errors.New("file not found at revision")
+
+// LoadYamlAtRevision unmarshals the file at path (relative to the repository root) as it was committed at revision,
+// where revision is anything git rev-parse accepts such as a tag, branch, SHA or HEAD
+func LoadYamlAtRevision(revision string, path string, destVar interface{}) error {
+	commit, err := GetRevisionCommit(revision)
+	if err != nil {
+		return err
+	}
+
+	file, err := commit.File(path)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return fmt.Errorf("%s at %s: %w", path, revision, ErrFileNotFound)
+		}
+		return err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return err
+	}
+
+	return goyaml.Unmarshal([]byte(contents), destVar)
+}
+
+// GetRevisionCommit returns the commit a revision points to
+func GetRevisionCommit(revision string) (*object.Commit, error) {
+	repo, err := OpenRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve revision %s: %w", revision, err)
+	}
+
+	return repo.CommitObject(*hash)
+}
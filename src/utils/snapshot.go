@@ -0,0 +1,63 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// NightlyVersion is the version of the rolling nightly-<flavor> tag and release that points to the latest snapshot
+const NightlyVersion = "nightly"
+
+const snapshotDateFormat = "20060102"
+
+var snapshotRegex = regexp.MustCompile(`^(.+)-dev\.(\d{8})\.([0-9a-f]{7,40})$`)
+
+// SnapshotVersion returns the <version>-<flavor>-dev.<date>.<shortsha> snapshot of the flavor's version built from the
+// commit. The date is the day the commit was committed in UTC rather than the day of the build so that every step of
+// a pipeline computes the same version. As the snapshot already carries the flavor it is also its own tag.
+func SnapshotVersion(version string, flavor string, commit *object.Commit) string {
+	date := commit.Committer.When.UTC().Format(snapshotDateFormat)
+	return fmt.Sprintf("%s-%s-dev.%s.%s", version, flavor, date, ShortSHA(commit.Hash.String()))
+}
+
+// GetSnapshotFlavor returns the flavor with its version replaced by the snapshot of the version built from HEAD
+func GetSnapshotFlavor(flavor types.Flavor) (types.Flavor, error) {
+	commit, err := GetRevisionCommit("HEAD")
+	if err != nil {
+		return flavor, err
+	}
+
+	flavor.Version = SnapshotVersion(flavor.Version, flavor.Name, commit)
+	return flavor, nil
+}
+
+// ParseSnapshot returns the version a <version>-<flavor>-dev.<date>.<shortsha> snapshot of the flavor was built from
+// and its date, ok is false when the version is not a snapshot of the flavor
+func ParseSnapshot(version string, flavor string) (base string, date time.Time, ok bool) {
+	matches := snapshotRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return version, time.Time{}, false
+	}
+	base, found := strings.CutSuffix(matches[1], "-"+flavor)
+	if !found || base == "" {
+		return version, time.Time{}, false
+	}
+	date, err := time.Parse(snapshotDateFormat, matches[2])
+	if err != nil {
+		return version, time.Time{}, false
+	}
+	return base, date, true
+}
+
+// NightlyTag returns the rolling tag of the flavor's latest snapshot
+func NightlyTag(flavor string) string {
+	return fmt.Sprintf("%s-%s", NightlyVersion, flavor)
+}
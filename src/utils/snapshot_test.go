@@ -0,0 +1,59 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSnapshotFlavor(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	// The date is the commit date in UTC, which is the previous day in this timezone
+	when := time.Date(2026, 10, 19, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: when}
+	hash, err := worktree.Commit("snapshot", &git.CommitOptions{AllowEmptyCommits: true, Author: signature})
+	require.NoError(t, err)
+
+	flavor, err := GetSnapshotFlavor(types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0-upstream-dev.20261020."+hash.String()[:8], flavor.Version)
+
+	base, date, ok := ParseSnapshot(flavor.Version, "upstream")
+	require.True(t, ok)
+	require.Equal(t, "1.0.0-uds.0", base)
+	require.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), date)
+}
+
+func TestParseSnapshot(t *testing.T) {
+	tests := []struct {
+		version      string
+		expectedBase string
+		expectedOk   bool
+	}{
+		{version: "1.0.0-uds.0-upstream-dev.20261019.abc12345", expectedBase: "1.0.0-uds.0", expectedOk: true},
+		{version: "1.0.0-uds.0-rc.1-upstream-dev.20261019.abc1234def", expectedBase: "1.0.0-uds.0-rc.1", expectedOk: true},
+		{version: "1.0.0-uds.0", expectedBase: "1.0.0-uds.0", expectedOk: false},
+		{version: "1.0.0-uds.0-registry1-dev.20261019.abc12345", expectedBase: "1.0.0-uds.0-registry1-dev.20261019.abc12345", expectedOk: false},
+		{version: "1.0.0-uds.0-upstream-dev.2026.abc12345", expectedBase: "1.0.0-uds.0-upstream-dev.2026.abc12345", expectedOk: false},
+		{version: "1.0.0-uds.0-upstream-dev.20261399.abc12345", expectedBase: "1.0.0-uds.0-upstream-dev.20261399.abc12345", expectedOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			base, _, ok := ParseSnapshot(tt.version, "upstream")
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expectedBase, base)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"cmp"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GitTag is an existing <version>-<flavor> git tag
+type GitTag struct {
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	CommitSHA  string    `json:"commitSha"`
+	CommitDate time.Time `json:"commitDate"`
+}
+
+// GetFlavorTags returns the tags created for a flavor sorted from oldest to newest version.
+// flavors is the full list of configured flavors so that a tag of a flavor whose name ends
+// with -<flavor> (e.g. upstream-fips for fips) is not mistaken for one of this flavor.
+func GetFlavorTags(flavor string, flavors []types.Flavor) ([]GitTag, error) {
+	repo, err := OpenRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var flavorTags []GitTag
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		tagName := ref.Name().Short()
+		version, ok := ParseFlavorTag(tagName, flavor, flavors)
+		if !ok {
+			return nil
+		}
+
+		commit, err := resolveTagCommit(repo, ref)
+		if err != nil {
+			return err
+		}
+
+		flavorTags = append(flavorTags, GitTag{
+			Name:       tagName,
+			Version:    version,
+			CommitSHA:  commit.Hash.String(),
+			CommitDate: commit.Committer.When,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(flavorTags, func(i, j int) bool {
+		return CompareVersions(flavorTags[i].Version, flavorTags[j].Version, flavor) < 0
+	})
+
+	return flavorTags, nil
+}
+
+// GetLatestFlavorTag returns the tag with the highest version for a flavor, or nil if the flavor has never been tagged
+func GetLatestFlavorTag(flavor string, flavors []types.Flavor) (*GitTag, error) {
+	flavorTags, err := GetFlavorTags(flavor, flavors)
+	if err != nil || len(flavorTags) == 0 {
+		return nil, err
+	}
+
+	return &flavorTags[len(flavorTags)-1], nil
+}
+
+// GetPreviousFlavorTag returns the tag with the highest version lower than the flavor's configured version,
+// or nil if the flavor has not been released before
+func GetPreviousFlavorTag(flavor types.Flavor, flavors []types.Flavor) (*GitTag, error) {
+	flavorTags, err := GetFlavorTags(flavor.Name, flavors)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(flavorTags) - 1; i >= 0; i-- {
+		if CompareVersions(flavorTags[i].Version, flavor.Version, flavor.Name) < 0 {
+			return &flavorTags[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ParseFlavorTag returns the version of a <version>-<flavor> tag and whether the tag belongs to the flavor, the rolling
+// nightly-<flavor> tag is not a version of the flavor. A <version>-<flavor>-dev.<date>.<shortsha> snapshot tag is
+// its own version.
+func ParseFlavorTag(tag string, flavor string, flavors []types.Flavor) (string, bool) {
+	named := tag
+	matches := snapshotRegex.FindStringSubmatch(tag)
+	if matches != nil {
+		named = matches[1]
+	}
+
+	version, found := strings.CutSuffix(named, "-"+flavor)
+	if !found || version == "" || version == NightlyVersion {
+		return "", false
+	}
+
+	// Skip tags that belong to a longer flavor name sharing this suffix
+	for _, other := range flavors {
+		if other.Name != flavor && strings.HasSuffix(other.Name, "-"+flavor) && strings.HasSuffix(named, "-"+other.Name) {
+			return "", false
+		}
+	}
+
+	if matches != nil {
+		return tag, true
+	}
+	return version, true
+}
+
+var releaseCandidateRegex = regexp.MustCompile(`^(.+)-rc\.(\d+)$`)
+
+// ParseReleaseCandidate returns the final version of a <version>-rc.<N> release candidate and N, ok is false when
+// the version is not a release candidate
+func ParseReleaseCandidate(version string) (final string, candidate int, ok bool) {
+	matches := releaseCandidateRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return version, 0, false
+	}
+	candidate, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return version, 0, false
+	}
+	return matches[1], candidate, true
+}
+
+// CompareVersions compares two versions using semver ordering (so 1.0.0-uds.10 is newer than 1.0.0-uds.9),
+// falling back to a string comparison when either version is not valid semver. Release candidates come before their
+// final version, which semver alone would order the other way around as -rc.N extends the uds prerelease. Snapshots
+// of the flavor come after the version they were built from and are ordered by their date.
+func CompareVersions(a, b string, flavor string) int {
+	baseA, dateA, isSnapshotA := ParseSnapshot(a, flavor)
+	baseB, dateB, isSnapshotB := ParseSnapshot(b, flavor)
+	if isSnapshotA || isSnapshotB {
+		if result := CompareVersions(baseA, baseB, flavor); result != 0 {
+			return result
+		}
+		switch {
+		case !isSnapshotA:
+			return -1
+		case !isSnapshotB:
+			return 1
+		case !dateA.Equal(dateB):
+			return dateA.Compare(dateB)
+		default:
+			return strings.Compare(a, b)
+		}
+	}
+
+	finalA, candidateA, isCandidateA := ParseReleaseCandidate(a)
+	finalB, candidateB, isCandidateB := ParseReleaseCandidate(b)
+	if (isCandidateA || isCandidateB) && compareFinalVersions(finalA, finalB) == 0 {
+		switch {
+		case !isCandidateA:
+			return 1
+		case !isCandidateB:
+			return -1
+		default:
+			return cmp.Compare(candidateA, candidateB)
+		}
+	}
+	return compareFinalVersions(finalA, finalB)
+}
+
+func compareFinalVersions(a, b string) int {
+	versionA, errA := semver.NewVersion(a)
+	versionB, errB := semver.NewVersion(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return versionA.Compare(versionB)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func resolveTagCommit(repo *git.Repository, ref *plumbing.Reference) (*object.Commit, error) {
+	// Annotated tags point to a tag object rather than directly to the commit
+	if tagObject, err := repo.TagObject(ref.Hash()); err == nil {
+		return tagObject.Commit()
+	}
+
+	return repo.CommitObject(ref.Hash())
+}
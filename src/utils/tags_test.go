@@ -0,0 +1,131 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+var testFlavors = []types.Flavor{{Name: "upstream"}, {Name: "registry1"}, {Name: "unicorn"}, {Name: "fips-unicorn"}}
+
+func TestParseFlavorTag(t *testing.T) {
+	tests := []struct {
+		tag             string
+		flavor          string
+		expectedVersion string
+		expectedMatch   bool
+	}{
+		{tag: "1.0.0-uds.0-upstream", flavor: "upstream", expectedVersion: "1.0.0-uds.0", expectedMatch: true},
+		{tag: "1.0.0-uds.0-registry1", flavor: "upstream", expectedMatch: false},
+		{tag: "1.0.0-uds.0-unicorn", flavor: "unicorn", expectedVersion: "1.0.0-uds.0", expectedMatch: true},
+		{tag: "1.0.0-uds.0-fips-unicorn", flavor: "unicorn", expectedMatch: false},
+		{tag: "1.0.0-uds.0-fips-unicorn", flavor: "fips-unicorn", expectedVersion: "1.0.0-uds.0", expectedMatch: true},
+		{tag: "-upstream", flavor: "upstream", expectedMatch: false},
+		{tag: "nightly-upstream", flavor: "upstream", expectedMatch: false},
+		{tag: "1.0.0-uds.0-upstream-dev.20261019.abc12345", flavor: "upstream", expectedVersion: "1.0.0-uds.0-upstream-dev.20261019.abc12345", expectedMatch: true},
+		{tag: "1.0.0-uds.0-fips-unicorn-dev.20261019.abc12345", flavor: "unicorn", expectedMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.flavor, func(t *testing.T) {
+			version, match := ParseFlavorTag(tt.tag, tt.flavor, testFlavors)
+			require.Equal(t, tt.expectedMatch, match)
+			require.Equal(t, tt.expectedVersion, version)
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.0.0-uds.0", b: "1.0.0-uds.0", expected: 0},
+		{a: "1.0.0-uds.9", b: "1.0.0-uds.10", expected: -1},
+		{a: "1.0.1-uds.0", b: "1.0.0-uds.3", expected: 1},
+		{a: "1.0.0-rc.1-uds.0", b: "1.0.0-uds.0", expected: -1},
+		{a: "1.0.0-uds.0-rc.1", b: "1.0.0-uds.0", expected: -1},
+		{a: "1.0.0-uds.0-rc.10", b: "1.0.0-uds.0-rc.9", expected: 1},
+		{a: "1.0.0-uds.1-rc.1", b: "1.0.0-uds.0", expected: 1},
+		{a: "1.0.0-uds.1-rc.1", b: "1.0.0-uds.1-rc.1", expected: 0},
+		{a: "1.0.0-uds.0-upstream-dev.20261019.abc12345", b: "1.0.0-uds.0", expected: 1},
+		{a: "1.0.0-uds.0-upstream-dev.20261019.abc12345", b: "1.0.0-uds.1-rc.1", expected: -1},
+		{a: "1.0.0-uds.9-upstream-dev.20261019.abc12345", b: "1.0.0-uds.10", expected: -1},
+		{a: "1.0.0-uds.0-upstream-dev.20261019.abc12345", b: "1.0.0-uds.0-upstream-dev.20261001.def56789", expected: 1},
+		{a: "1.0.0-uds.1-rc.1-upstream-dev.20261019.abc12345", b: "1.0.0-uds.1-rc.1", expected: 1},
+		{a: "testing", b: "1.0.0-uds.0", expected: -1},
+		{a: "devel", b: "testing", expected: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			require.Equal(t, tt.expected, CompareVersions(tt.a, tt.b, "upstream"))
+		})
+	}
+}
+
+func TestParseReleaseCandidate(t *testing.T) {
+	final, candidate, ok := ParseReleaseCandidate("1.0.0-uds.0-rc.2")
+	require.True(t, ok)
+	require.Equal(t, "1.0.0-uds.0", final)
+	require.Equal(t, 2, candidate)
+
+	final, _, ok = ParseReleaseCandidate("1.0.0-uds.0")
+	require.False(t, ok)
+	require.Equal(t, "1.0.0-uds.0", final)
+}
+
+func TestGetFlavorTags(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+	for _, tag := range []string{"1.0.0-uds.10-upstream", "1.0.0-uds.9-upstream", "1.0.0-uds.0-fips-unicorn", "1.0.1-uds.0-unicorn"} {
+		signature.When = signature.When.Add(time.Hour)
+		hash, err := worktree.Commit(tag, &git.CommitOptions{AllowEmptyCommits: true, Author: signature})
+		require.NoError(t, err)
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+
+	// Annotated tags resolve to the commit they point at
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("1.0.0-uds.0-upstream", head.Hash(), &git.CreateTagOptions{Tagger: signature, Message: "annotated"})
+	require.NoError(t, err)
+
+	tags, err := GetFlavorTags("upstream", testFlavors)
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	require.Equal(t, "1.0.0-uds.0", tags[0].Version)
+	require.Equal(t, head.Hash().String(), tags[0].CommitSHA)
+	require.Equal(t, "1.0.0-uds.9", tags[1].Version)
+	require.Equal(t, "1.0.0-uds.10-upstream", tags[2].Name)
+
+	latest, err := GetLatestFlavorTag("unicorn", testFlavors)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.1-uds.0-unicorn", latest.Name)
+	require.Equal(t, time.Unix(0, 0).Add(4*time.Hour).Unix(), latest.CommitDate.Unix())
+
+	latest, err = GetLatestFlavorTag("registry1", testFlavors)
+	require.NoError(t, err)
+	require.Nil(t, latest)
+
+	previous, err := GetPreviousFlavorTag(types.Flavor{Name: "upstream", Version: "1.0.0-uds.10"}, testFlavors)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.9-upstream", previous.Name)
+
+	previous, err = GetPreviousFlavorTag(types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}, testFlavors)
+	require.NoError(t, err)
+	require.Nil(t, previous)
+}
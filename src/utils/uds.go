@@ -0,0 +1,35 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"path/filepath"
+
+	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/types"
+)
+
+// DefaultBundleFile is used when a flavor does not declare its own bundle files
+const DefaultBundleFile = "bundle/uds-bundle.yaml"
+
+// GetBundleFiles returns the uds-bundle.yaml files for a flavor, or only the one in bundleDir when it is set
+func GetBundleFiles(flavor types.Flavor, bundleDir string) []string {
+	if bundleDir != "" {
+		return []string{filepath.Join(bundleDir, "uds-bundle.yaml")}
+	}
+	if len(flavor.BundleFiles) > 0 {
+		return flavor.BundleFiles
+	}
+	return []string{DefaultBundleFile}
+}
+
+func GetBundleName(path string) (string, error) {
+	var bundle uds.UDSBundle
+	err := LoadYaml(path, &bundle)
+	if err != nil {
+		return "", err
+	}
+
+	return bundle.Metadata.Name, nil
+}
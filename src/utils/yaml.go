@@ -4,11 +4,16 @@
 package utils
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/defenseunicorns/uds-pk/src/types"
 	goyaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
 )
 
 func LoadReleaseConfig(dir string) (types.ReleaseConfig, error) {
@@ -22,6 +27,71 @@ func LoadReleaseConfig(dir string) (types.ReleaseConfig, error) {
 	return config, nil
 }
 
+// SetFlavorVersion sets the version of a flavor in the releaser.yaml in dir. Only the version value is rewritten so
+// the comments and formatting of the file are kept.
+func SetFlavorVersion(dir string, flavor string, version string) error {
+	path := filepath.Join(dir, "releaser.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var config types.ReleaseConfig
+	if err := goyaml.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	index := -1
+	for i, f := range config.Flavors {
+		if f.Name == flavor {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("flavor %s not found in %s", flavor, path)
+	}
+
+	file, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	versionPath, err := goyaml.PathString(fmt.Sprintf("$.flavors[%d].version", index))
+	if err != nil {
+		return err
+	}
+	node, err := versionPath.FilterFile(file)
+	if err != nil {
+		return fmt.Errorf("flavor %s has no version in %s: %w", flavor, path, err)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	position := node.GetToken().Position
+	line := lines[position.Line-1]
+	start := position.Column - 1
+	scalar := yamlScalarRegex.FindString(line[start:])
+	lines[position.Line-1] = line[:start] + quoteLike(scalar, version) + line[start+len(scalar):]
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "")), info.Mode())
+}
+
+// quoteLike quotes the value the same way as the scalar it replaces so the quoting style of the file is kept
+func quoteLike(scalar string, value string) string {
+	switch {
+	case strings.HasPrefix(scalar, `"`):
+		return strconv.Quote(value)
+	case strings.HasPrefix(scalar, "'"):
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	default:
+		return value
+	}
+}
+
+// yamlScalarRegex matches a quoted or plain scalar at the start of a line
+var yamlScalarRegex = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#,}\]]+)`)
+
 func LoadYaml(path string, destVar interface{}) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
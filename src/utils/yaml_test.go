@@ -0,0 +1,47 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFlavorVersion(t *testing.T) {
+	dir := t.TempDir()
+	releaser := `# Copyright
+
+flavors:
+  # the default flavor
+  - name: upstream
+    version: "1.0.0-uds.0" # bumped by release pr
+    publishBundle: true
+  - {name: registry1, version: 1.0.0-uds.1}
+  - name: unicorn
+    version: '1.0.0-uds.2'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "releaser.yaml"), []byte(releaser), 0644))
+
+	require.NoError(t, SetFlavorVersion(dir, "upstream", "1.0.1-uds.0"))
+	require.NoError(t, SetFlavorVersion(dir, "registry1", "1.0.0-uds.2"))
+	require.NoError(t, SetFlavorVersion(dir, "unicorn", "2.0.0-uds.0"))
+	require.ErrorContains(t, SetFlavorVersion(dir, "missing", "1.0.0"), "flavor missing not found")
+
+	data, err := os.ReadFile(filepath.Join(dir, "releaser.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, `# Copyright
+
+flavors:
+  # the default flavor
+  - name: upstream
+    version: "1.0.1-uds.0" # bumped by release pr
+    publishBundle: true
+  - {name: registry1, version: 1.0.0-uds.2}
+  - name: unicorn
+    version: '2.0.0-uds.0'
+`, string(data))
+}
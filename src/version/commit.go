@@ -0,0 +1,145 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// DefaultCommitMessage is the commit message template used when committing the updated yamls
+const DefaultCommitMessage = "chore: release {{ .Package }} {{ .Tag }}"
+
+// CommitOptions configures committing and pushing the files changed by UpdateYamls
+type CommitOptions struct {
+	// Message is a Go template with the fields Package, Flavor, Version and Tag
+	Message string
+	// Paths are other files to commit along with the yamls when they have changes, such as the changelog
+	Paths []string
+	// SigningKeyVarName is the environment variable holding the armored OpenPGP key to sign the commit with, if any
+	SigningKeyVarName string
+	Push              bool
+	TokenVarName      string
+	HTTPConfig        types.HTTPConfig
+}
+
+// CommitData is the data available to the commit message template
+type CommitData struct {
+	Package string
+	Flavor  string
+	Version string
+	Tag     string
+}
+
+// CommitYamls commits the zarf.yaml and bundle files of the flavor and opts.Paths that have uncommitted changes,
+// leaving any other changes in the worktree alone, and optionally pushes the commit to the current branch on origin
+func CommitYamls(flavor types.Flavor, opts CommitOptions) error {
+	paths, err := changedFiles(append(yamlFiles(flavor), opts.Paths...))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		message.Infof("No changes to commit for %s-%s\n", flavor.Version, flavor.Name)
+		return nil
+	}
+
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return err
+	}
+
+	commitMessage, err := RenderCommitMessage(opts.Message, CommitData{
+		Package: packageName,
+		Flavor:  flavor.Name,
+		Version: flavor.Version,
+		Tag:     fmt.Sprintf("%s-%s", flavor.Version, flavor.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	var branch string
+	if opts.Push {
+		// Resolve the branch before committing so a detached HEAD without a CI branch fails without a stray commit
+		branch, err = utils.GetCurrentBranch()
+		if err != nil {
+			return err
+		}
+	}
+
+	commit, err := utils.CommitFiles(paths, commitMessage, opts.SigningKeyVarName)
+	if err != nil {
+		return err
+	}
+	message.Infof("Committed %s as %s\n", strings.Join(paths, ", "), utils.ShortSHA(commit))
+
+	if !opts.Push {
+		return nil
+	}
+
+	remoteURL, _, err := utils.GetRepoInfo()
+	if err != nil {
+		return err
+	}
+	if err := utils.PushCommit(commit, branch, false, utils.GitAuth(remoteURL, opts.TokenVarName), opts.HTTPConfig); err != nil {
+		return fmt.Errorf("unable to push to origin/%s: %w", branch, err)
+	}
+	message.Infof("Pushed %s to origin/%s\n", utils.ShortSHA(commit), branch)
+	return nil
+}
+
+// ChangedYamls returns the zarf.yaml and bundle files of the flavor that have uncommitted changes
+func ChangedYamls(flavor types.Flavor) ([]string, error) {
+	return changedFiles(yamlFiles(flavor))
+}
+
+func yamlFiles(flavor types.Flavor) []string {
+	return append([]string{"zarf.yaml"}, utils.GetBundleFiles(flavor, "")...)
+}
+
+// changedFiles returns the candidate paths that have uncommitted changes
+func changedFiles(candidates []string) ([]string, error) {
+	changes, err := utils.GetUncommittedChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range candidates {
+		path = filepath.ToSlash(filepath.Clean(path))
+		for _, changed := range changes {
+			if changed == path {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// RenderCommitMessage executes the commit message template, using DefaultCommitMessage when it is empty
+func RenderCommitMessage(text string, data CommitData) (string, error) {
+	if text == "" {
+		text = DefaultCommitMessage
+	}
+
+	tmpl, err := template.New("commit").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid commit message template: %w", err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("invalid commit message template: %w", err)
+	}
+	return rendered.String(), nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/defenseunicorns/uds-pk/src/test/testutil"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitYamls(t *testing.T) {
+	originDir := t.TempDir()
+	origin, err := git.PlainInit(originDir, true)
+	require.NoError(t, err)
+
+	testutil.Chdir(t)
+
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+
+	repo, err := git.PlainInit(".", false)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}})
+	require.NoError(t, err)
+
+	require.NoError(t, os.Mkdir("bundle", 0755))
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("metadata:\n  name: test\n  version: 1.0.0-uds.0\n"), 0644))
+	require.NoError(t, os.WriteFile("bundle/uds-bundle.yaml", []byte("metadata:\n  version: 1.0.0-uds.0\n"), 0644))
+	require.NoError(t, os.WriteFile("README.md", []byte("readme"), 0644))
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.1-uds.0"}
+
+	// Nothing is committed when the yamls are unchanged
+	require.NoError(t, CommitYamls(flavor, CommitOptions{}))
+	head, err := repo.Head()
+	require.NoError(t, err)
+	initial := head.Hash()
+
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("metadata:\n  name: test\n  version: 1.0.1-uds.0\n"), 0644))
+	require.NoError(t, os.WriteFile("bundle/uds-bundle.yaml", []byte("metadata:\n  version: 1.0.1-uds.0\n"), 0644))
+	require.NoError(t, os.WriteFile("README.md", []byte("unrelated change"), 0644))
+	require.NoError(t, os.WriteFile("CHANGELOG.md", []byte("# Changelog\n"), 0644))
+
+	changed, err := ChangedYamls(flavor)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bundle/uds-bundle.yaml", "zarf.yaml"}, changed)
+
+	key, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	var armoredKey bytes.Buffer
+	writer, err := armor.Encode(&armoredKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, key.SerializePrivate(writer, nil))
+	require.NoError(t, writer.Close())
+	t.Setenv("TEST_SIGNING_KEY", armoredKey.String())
+
+	err = CommitYamls(flavor, CommitOptions{Message: "release {{ .Flavor }} {{ .Version }}", Paths: []string{"CHANGELOG.md"}, SigningKeyVarName: "TEST_SIGNING_KEY", Push: true})
+	require.NoError(t, err)
+
+	head, err = repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	require.Equal(t, "release upstream 1.0.1-uds.0", commit.Message)
+	require.Equal(t, initial, commit.ParentHashes[0])
+	require.NotEmpty(t, commit.PGPSignature)
+	_, err = commit.Verify(armoredKey.String())
+	require.NoError(t, err)
+
+	stats, err := commit.Stats()
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	// Unrelated changes are left in the worktree
+	status, err := worktree.Status()
+	require.NoError(t, err)
+	require.Equal(t, git.Modified, status.File("README.md").Worktree)
+
+	pushed, err := origin.Reference(plumbing.NewBranchReferenceName("master"), false)
+	require.NoError(t, err)
+	require.Equal(t, head.Hash(), pushed.Hash())
+}
+
+func TestCommitYamlsStagedChanges(t *testing.T) {
+	repo := testutil.InitRepo(t)
+
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("metadata:\n  name: test\n  version: 1.0.0-uds.0\n"), 0644))
+	require.NoError(t, os.WriteFile("README.md", []byte("readme"), 0644))
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("zarf.yaml", []byte("metadata:\n  name: test\n  version: 1.0.1-uds.0\n"), 0644))
+	require.NoError(t, os.WriteFile("README.md", []byte("unrelated staged change"), 0644))
+	_, err = worktree.Add("README.md")
+	require.NoError(t, err)
+
+	// The staged README.md would be part of the release commit
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.1-uds.0"}
+	err = CommitYamls(flavor, CommitOptions{Message: DefaultCommitMessage})
+	require.ErrorContains(t, err, "other staged changes to README.md")
+
+	current, err := repo.Head()
+	require.NoError(t, err)
+	require.Equal(t, head.Hash(), current.Hash())
+	status, err := worktree.Status()
+	require.NoError(t, err)
+	require.Equal(t, git.Unmodified, status.File("zarf.yaml").Staging)
+}
+
+func TestRenderCommitMessage(t *testing.T) {
+	data := CommitData{Package: "podinfo", Flavor: "upstream", Version: "1.0.0-uds.0", Tag: "1.0.0-uds.0-upstream"}
+
+	rendered, err := RenderCommitMessage("", data)
+	require.NoError(t, err)
+	require.Equal(t, "chore: release podinfo 1.0.0-uds.0-upstream", rendered)
+
+	_, err = RenderCommitMessage("{{ .Missing", data)
+	require.ErrorContains(t, err, "invalid commit message template")
+}
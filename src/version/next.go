@@ -0,0 +1,64 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+)
+
+var udsRevisionRegex = regexp.MustCompile(`^(.*uds\.)(\d+)$`)
+
+// Next returns the version after current. Bumping the major, minor or patch version starts a new uds.0 revision,
+// bumping uds increments the uds revision of the same upstream version. Bumping rc increments the release candidate
+// of a -rc.N version or starts the first release candidate of the next uds revision, the other bumps drop the
+// release candidate of current.
+func Next(current string, bump string) (string, error) {
+	final, candidate, isCandidate := utils.ParseReleaseCandidate(current)
+	if bump == "rc" {
+		if isCandidate {
+			return fmt.Sprintf("%s-rc.%d", final, candidate+1), nil
+		}
+		next, err := Next(current, "uds")
+		if err != nil {
+			return "", err
+		}
+		return next + "-rc.1", nil
+	}
+	current = final
+
+	v, err := semver.NewVersion(current)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse version %s: %w", current, err)
+	}
+
+	var next semver.Version
+	switch bump {
+	case "major":
+		next = v.IncMajor()
+	case "minor":
+		next = v.IncMinor()
+	case "patch":
+		// IncPatch only drops the prerelease of versions that have one, so bump it explicitly
+		next = *semver.New(v.Major(), v.Minor(), v.Patch()+1, "", "")
+	case "uds":
+		matches := udsRevisionRegex.FindStringSubmatch(v.Prerelease())
+		if matches == nil {
+			return "", fmt.Errorf("version %s does not have a uds revision to bump", current)
+		}
+		revision, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d.%d.%d-%s%d", v.Major(), v.Minor(), v.Patch(), matches[1], revision+1), nil
+	default:
+		return "", fmt.Errorf("unsupported bump %q, must be major, minor, patch, uds or rc", bump)
+	}
+
+	return fmt.Sprintf("%s-uds.0", next.String()), nil
+}
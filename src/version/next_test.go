@@ -0,0 +1,46 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		current       string
+		bump          string
+		expected      string
+		expectedError string
+	}{
+		{current: "1.0.0-uds.0", bump: "uds", expected: "1.0.0-uds.1"},
+		{current: "1.0.0-uds.9", bump: "uds", expected: "1.0.0-uds.10"},
+		{current: "1.0.0-rc.1-uds.2", bump: "uds", expected: "1.0.0-rc.1-uds.3"},
+		{current: "1.2.3-uds.4", bump: "patch", expected: "1.2.4-uds.0"},
+		{current: "1.2.3-uds.4", bump: "minor", expected: "1.3.0-uds.0"},
+		{current: "1.2.3-uds.4", bump: "major", expected: "2.0.0-uds.0"},
+		{current: "1.2.3", bump: "patch", expected: "1.2.4-uds.0"},
+		{current: "1.0.0-uds.0", bump: "rc", expected: "1.0.0-uds.1-rc.1"},
+		{current: "1.0.0-uds.1-rc.1", bump: "rc", expected: "1.0.0-uds.1-rc.2"},
+		{current: "1.0.0-uds.1-rc.2", bump: "uds", expected: "1.0.0-uds.2"},
+		{current: "1.2.3-uds.1-rc.2", bump: "minor", expected: "1.3.0-uds.0"},
+		{current: "1.2.3", bump: "uds", expectedError: "does not have a uds revision"},
+		{current: "testing", bump: "uds", expectedError: "unable to parse version testing"},
+		{current: "1.2.3-uds.4", bump: "build", expectedError: `unsupported bump "build"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+" "+tt.bump, func(t *testing.T) {
+			next, err := Next(tt.current, tt.bump)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, next)
+		})
+	}
+}
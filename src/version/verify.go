@@ -0,0 +1,116 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// Mismatch is a version field in the zarf.yaml or a uds-bundle.yaml that does not match the flavor
+type Mismatch struct {
+	File     string `json:"file"`
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s %s is %q, expected %q", m.File, m.Field, m.Actual, m.Expected)
+}
+
+// VerifyYamls returns the version fields that update-yaml would change for the flavor
+func VerifyYamls(flavor types.Flavor) ([]Mismatch, error) {
+	var zarfPackage zarf.ZarfPackage
+	err := utils.LoadYaml("zarf.yaml", &zarfPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []Mismatch
+	if zarfPackage.Metadata.Version != flavor.Version {
+		mismatches = append(mismatches, Mismatch{
+			File:     "zarf.yaml",
+			Field:    "metadata.version",
+			Expected: flavor.Version,
+			Actual:   zarfPackage.Metadata.Version,
+		})
+	}
+
+	for _, bundlePath := range utils.GetBundleFiles(flavor, "") {
+		bundleMismatches, err := verifyBundleYaml(bundlePath, flavor, zarfPackage.Metadata.Name)
+		// Packages without a bundle only have the default bundle file, which may not exist
+		if errors.Is(err, fs.ErrNotExist) && len(flavor.BundleFiles) == 0 {
+			continue
+		}
+		if err != nil {
+			return mismatches, err
+		}
+		mismatches = append(mismatches, bundleMismatches...)
+	}
+
+	return mismatches, nil
+}
+
+// WriteDiff renders the mismatches as a diff from the current to the expected value, grouped by file
+func WriteDiff(w io.Writer, mismatches []Mismatch) error {
+	file := ""
+	for _, mismatch := range mismatches {
+		if mismatch.File != file {
+			file = mismatch.File
+			if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", file, file); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "-%s: %s\n+%s: %s\n", mismatch.Field, mismatch.Actual, mismatch.Field, mismatch.Expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyBundleYaml(bundlePath string, flavor types.Flavor, packageName string) ([]Mismatch, error) {
+	var bundle uds.UDSBundle
+	err := utils.LoadYaml(bundlePath, &bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []Mismatch
+	if bundle.Metadata.Version != flavor.Version {
+		mismatches = append(mismatches, Mismatch{
+			File:     bundlePath,
+			Field:    "metadata.version",
+			Expected: flavor.Version,
+			Actual:   bundle.Metadata.Version,
+		})
+	}
+
+	for i, bundledPackage := range bundle.Packages {
+		if bundledPackage.Name != packageName {
+			continue
+		}
+		expected, err := BundleRef(bundledPackage, flavor)
+		if err != nil {
+			return mismatches, err
+		}
+		if bundledPackage.Ref != expected {
+			mismatches = append(mismatches, Mismatch{
+				File:     bundlePath,
+				Field:    fmt.Sprintf("packages[%d].ref", i),
+				Expected: expected,
+				Actual:   bundledPackage.Ref,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDiff(t *testing.T) {
+	mismatches := []Mismatch{
+		{File: "zarf.yaml", Field: "metadata.version", Expected: "1.0.1-uds.0", Actual: "1.0.0-uds.0"},
+		{File: "bundle/uds-bundle.yaml", Field: "metadata.version", Expected: "1.0.1-uds.0", Actual: "1.0.0-uds.0"},
+		{File: "bundle/uds-bundle.yaml", Field: "packages[1].ref", Expected: "1.0.1-uds.0-upstream", Actual: "1.0.0-uds.0-upstream"},
+	}
+
+	var out strings.Builder
+	require.NoError(t, WriteDiff(&out, mismatches))
+	require.Equal(t, `--- zarf.yaml
++++ zarf.yaml
+-metadata.version: 1.0.0-uds.0
++metadata.version: 1.0.1-uds.0
+--- bundle/uds-bundle.yaml
++++ bundle/uds-bundle.yaml
+-metadata.version: 1.0.0-uds.0
++metadata.version: 1.0.1-uds.0
+-packages[1].ref: 1.0.0-uds.0-upstream
++packages[1].ref: 1.0.1-uds.0-upstream
+`, out.String())
+}
@@ -4,20 +4,38 @@
 package version
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
 	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/hooks"
+	"github.com/defenseunicorns/uds-pk/src/oci"
 	"github.com/defenseunicorns/uds-pk/src/types"
 	"github.com/defenseunicorns/uds-pk/src/utils"
 	zarf "github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
-func UpdateYamls(flavor types.Flavor) error {
-	packageName, err := updateZarfYaml(flavor)
+// UpdateYamls runs the preUpdateYaml hooks and then sets the flavor's version in the zarf.yaml and bundles
+func UpdateYamls(flavor types.Flavor, globalHooks types.Hooks) error {
+	packageName, err := utils.GetPackageName()
+	if err != nil {
+		return err
+	}
+
+	metadata := hooks.Metadata{Package: packageName, Flavor: flavor}
+	if err := hooks.Run(context.Background(), hooks.PreUpdateYaml, globalHooks, metadata); err != nil {
+		return err
+	}
+
+	packageName, err = updateZarfYaml(flavor)
 	if err != nil {
 		return err
 	}
 
-	return updateBundleYaml(flavor, packageName)
+	return updateBundleYamls(flavor, packageName)
 }
 
 func updateZarfYaml(flavor types.Flavor) (packageName string, err error) {
@@ -39,11 +57,29 @@ func updateZarfYaml(flavor types.Flavor) (packageName string, err error) {
 	return zarfPackage.Metadata.Name, nil
 }
 
-func updateBundleYaml(flavor types.Flavor, packageName string) error {
+func updateBundleYamls(flavor types.Flavor, packageName string) error {
+	updated := 0
+	for _, bundlePath := range utils.GetBundleFiles(flavor, "") {
+		referenced, err := updateBundleYaml(bundlePath, flavor, packageName)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			updated++
+		}
+	}
+
+	if updated == 0 {
+		message.Warnf("No bundle references package %s\n", packageName)
+	}
+	return nil
+}
+
+func updateBundleYaml(bundlePath string, flavor types.Flavor, packageName string) (referenced bool, err error) {
 	var bundle uds.UDSBundle
-	err := utils.LoadYaml("bundle/uds-bundle.yaml", &bundle)
+	err = utils.LoadYaml(bundlePath, &bundle)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	bundle.Metadata.Version = flavor.Version
@@ -51,15 +87,51 @@ func updateBundleYaml(flavor types.Flavor, packageName string) error {
 	// Find the package that matches the package name and update its ref
 	for i, bundledPackage := range bundle.Packages {
 		if bundledPackage.Name == packageName {
-			bundle.Packages[i].Ref = flavor.Version
+			bundle.Packages[i].Ref, err = BundleRef(bundledPackage, flavor)
+			if err != nil {
+				return false, err
+			}
+			referenced = true
 		}
 	}
 
-	err = utils.UpdateYaml("bundle/uds-bundle.yaml", bundle)
+	err = utils.UpdateYaml(bundlePath, bundle)
 	if err != nil {
-		return err
+		return referenced, err
 	}
 
-	message.Infof("Updated uds-bundle.yaml with version %s\n", flavor.Version)
-	return nil
+	message.Infof("Updated %s with version %s\n", bundlePath, flavor.Version)
+	return referenced, nil
+}
+
+// BundleRef returns the ref a bundle should use for the flavor's package. Packages pulled from a repository use the
+// flavor's bundleRef template, defaulting to the published <version>-<flavor> tag, while local packages are found by
+// their version.
+func BundleRef(bundledPackage uds.Package, flavor types.Flavor) (string, error) {
+	if bundledPackage.Repository == "" {
+		return flavor.Version, nil
+	}
+	if flavor.BundleRef == "" {
+		return oci.FlavorTag(flavor), nil
+	}
+
+	tmpl, err := template.New("bundleRef").Option("missingkey=error").Parse(flavor.BundleRef)
+	if err != nil {
+		return "", fmt.Errorf("invalid bundleRef template for flavor %s: %w", flavor.Name, err)
+	}
+
+	var ref strings.Builder
+	data := BundleRefData{Flavor: flavor.Name, Version: flavor.Version, Tag: oci.FlavorTag(flavor)}
+	if err := tmpl.Execute(&ref, data); err != nil {
+		return "", fmt.Errorf("invalid bundleRef template for flavor %s: %w", flavor.Name, err)
+	}
+	return ref.String(), nil
+}
+
+// BundleRefData holds the fields available to a flavor's bundleRef template
+type BundleRefData struct {
+	Flavor  string
+	Version string
+	// Tag is the <version>-<flavor> tag the package is published with
+	Tag string
 }
@@ -0,0 +1,84 @@
+// Copyright 2024 Defense Unicorns
+// SPDX-License-Identifier: AGPL-3.0-or-later OR LicenseRef-Defense-Unicorns-Commercial
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	uds "github.com/defenseunicorns/uds-cli/src/types"
+	"github.com/defenseunicorns/uds-pk/src/types"
+	"github.com/defenseunicorns/uds-pk/src/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBundleYaml(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), "uds-bundle.yaml")
+	bundleYaml := `metadata:
+  name: testing-bundle
+  version: devel
+packages:
+  - name: testing-package
+    repository: ghcr.io/defenseunicorns/packages/uds/testing-package
+    ref: devel
+  - name: other-package
+    path: ../
+    ref: 0.1.0
+`
+	require.NoError(t, os.WriteFile(bundlePath, []byte(bundleYaml), 0o644))
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}
+
+	referenced, err := updateBundleYaml(bundlePath, flavor, "testing-package")
+	require.NoError(t, err)
+	require.True(t, referenced)
+	bundle := loadBundle(t, bundlePath)
+	require.Equal(t, "1.0.0-uds.0", bundle.Metadata.Version)
+	require.Equal(t, "1.0.0-uds.0-upstream", bundle.Packages[0].Ref)
+	require.Equal(t, "0.1.0", bundle.Packages[1].Ref)
+
+	// The version of bundles that pull the package under another name is still updated
+	flavor.Version = "1.0.1-uds.0"
+	referenced, err = updateBundleYaml(bundlePath, flavor, "renamed-package")
+	require.NoError(t, err)
+	require.False(t, referenced)
+	bundle = loadBundle(t, bundlePath)
+	require.Equal(t, "1.0.1-uds.0", bundle.Metadata.Version)
+	require.Equal(t, "1.0.0-uds.0-upstream", bundle.Packages[0].Ref)
+}
+
+func TestBundleRef(t *testing.T) {
+	flavor := types.Flavor{Name: "upstream", Version: "1.0.0-uds.0"}
+	remotePackage := uds.Package{Name: "testing-package", Repository: "ghcr.io/defenseunicorns/packages/uds/testing-package"}
+	localPackage := uds.Package{Name: "testing-package", Path: "../"}
+
+	ref, err := BundleRef(remotePackage, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0-upstream", ref)
+
+	ref, err = BundleRef(localPackage, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0", ref)
+
+	flavor.BundleRef = "{{ .Flavor }}-{{ .Version }}"
+	ref, err = BundleRef(remotePackage, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "upstream-1.0.0-uds.0", ref)
+
+	// The template only applies to packages pulled from a repository
+	ref, err = BundleRef(localPackage, flavor)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-uds.0", ref)
+
+	flavor.BundleRef = "{{ .Missing }}"
+	_, err = BundleRef(remotePackage, flavor)
+	require.ErrorContains(t, err, "invalid bundleRef template for flavor upstream")
+}
+
+func loadBundle(t *testing.T, bundlePath string) uds.UDSBundle {
+	t.Helper()
+	var bundle uds.UDSBundle
+	require.NoError(t, utils.LoadYaml(bundlePath, &bundle))
+	return bundle
+}